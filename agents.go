@@ -0,0 +1,299 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListAgents returns the agents linked to a scanner matching the given
+// options, which can be nil. On Tenable.io agents are linked to scanner 1.
+func (c *NessusClient) ListAgents(scannerID int64, opts *AgentListOptions) ([]Agent, error) {
+	return c.ListAgentsContext(context.Background(), scannerID, opts)
+}
+
+// ListAgentsContext returns the agents linked to a scanner matching the given
+// options using the given context.
+func (c *NessusClient) ListAgentsContext(ctx context.Context, scannerID int64, opts *AgentListOptions) ([]Agent, error) {
+	q := url.Values{}
+	if opts != nil {
+		for _, f := range opts.Filters {
+			q.Add("f", f.Field+":"+f.Operator+":"+f.Value)
+		}
+		if opts.FilterType != "" {
+			q.Set("ft", opts.FilterType)
+		}
+		if opts.Wildcard != "" {
+			q.Set("w", opts.Wildcard)
+		}
+	}
+
+	var agents []Agent
+	path := fmt.Sprintf("/scanners/%d/agents", scannerID)
+	err := c.getAllPages(ctx, path, q, "agents", func(raw json.RawMessage) (int, error) {
+		var page []Agent
+		err := json.Unmarshal(raw, &page)
+		agents = append(agents, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return agents, nil
+}
+
+// GetAgent retrieves an agent by ID
+func (c *NessusClient) GetAgent(scannerID, agentID int64) (*Agent, error) {
+	return c.GetAgentContext(context.Background(), scannerID, agentID)
+}
+
+// GetAgentContext retrieves an agent by ID using the given context.
+func (c *NessusClient) GetAgentContext(ctx context.Context, scannerID, agentID int64) (*Agent, error) {
+	path := fmt.Sprintf("/scanners/%d/agents/%d", scannerID, agentID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Agent{}
+	err = c.performCallAndReadResponse(req, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// UnlinkAgent unlinks the agent with the given ID
+func (c *NessusClient) UnlinkAgent(scannerID, agentID int64) error {
+	return c.UnlinkAgentContext(context.Background(), scannerID, agentID)
+}
+
+// UnlinkAgentContext unlinks the agent with the given ID using the given context.
+func (c *NessusClient) UnlinkAgentContext(ctx context.Context, scannerID, agentID int64) error {
+	path := fmt.Sprintf("/scanners/%d/agents/%d", scannerID, agentID)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// BulkUnlinkAgents starts a job unlinking the agents with the given IDs
+func (c *NessusClient) BulkUnlinkAgents(scannerID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	return c.BulkUnlinkAgentsContext(context.Background(), scannerID, agentIDs)
+}
+
+// BulkUnlinkAgentsContext starts a job unlinking the agents with the given IDs using the given context.
+func (c *NessusClient) BulkUnlinkAgentsContext(ctx context.Context, scannerID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	path := fmt.Sprintf("/scanners/%d/agents/_bulk/unlink", scannerID)
+	return c.agentBulkJob(ctx, path, agentIDs)
+}
+
+// BulkAddAgentsToGroup starts a job adding the agents with the given IDs to a group
+func (c *NessusClient) BulkAddAgentsToGroup(scannerID, groupID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	return c.BulkAddAgentsToGroupContext(context.Background(), scannerID, groupID, agentIDs)
+}
+
+// BulkAddAgentsToGroupContext starts a job adding the agents with the given
+// IDs to a group using the given context.
+func (c *NessusClient) BulkAddAgentsToGroupContext(ctx context.Context, scannerID, groupID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d/agents/_bulk/add", scannerID, groupID)
+	return c.agentBulkJob(ctx, path, agentIDs)
+}
+
+// BulkRemoveAgentsFromGroup starts a job removing the agents with the given IDs from a group
+func (c *NessusClient) BulkRemoveAgentsFromGroup(scannerID, groupID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	return c.BulkRemoveAgentsFromGroupContext(context.Background(), scannerID, groupID, agentIDs)
+}
+
+// BulkRemoveAgentsFromGroupContext starts a job removing the agents with the
+// given IDs from a group using the given context.
+func (c *NessusClient) BulkRemoveAgentsFromGroupContext(ctx context.Context, scannerID, groupID int64, agentIDs []int64) (*AgentBulkJob, error) {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d/agents/_bulk/remove", scannerID, groupID)
+	return c.agentBulkJob(ctx, path, agentIDs)
+}
+
+// GetAgentBulkJob returns the status of a bulk unlink job
+func (c *NessusClient) GetAgentBulkJob(scannerID int64, taskID string) (*AgentBulkJob, error) {
+	return c.GetAgentBulkJobContext(context.Background(), scannerID, taskID)
+}
+
+// GetAgentBulkJobContext returns the status of a bulk unlink job using the given context.
+func (c *NessusClient) GetAgentBulkJobContext(ctx context.Context, scannerID int64, taskID string) (*AgentBulkJob, error) {
+	path := fmt.Sprintf("/scanners/%d/agents/_bulk/%s", scannerID, url.PathEscape(taskID))
+	return c.getAgentBulkJob(ctx, path)
+}
+
+// GetAgentGroupBulkJob returns the status of a bulk group membership job
+func (c *NessusClient) GetAgentGroupBulkJob(scannerID, groupID int64, taskID string) (*AgentBulkJob, error) {
+	return c.GetAgentGroupBulkJobContext(context.Background(), scannerID, groupID, taskID)
+}
+
+// GetAgentGroupBulkJobContext returns the status of a bulk group membership job using the given context.
+func (c *NessusClient) GetAgentGroupBulkJobContext(ctx context.Context, scannerID, groupID int64, taskID string) (*AgentBulkJob, error) {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d/agents/_bulk/%s", scannerID, groupID, url.PathEscape(taskID))
+	return c.getAgentBulkJob(ctx, path)
+}
+
+// ListAgentGroups returns all the agent groups of a scanner
+func (c *NessusClient) ListAgentGroups(scannerID int64) ([]AgentGroup, error) {
+	return c.ListAgentGroupsContext(context.Background(), scannerID)
+}
+
+// ListAgentGroupsContext returns all the agent groups of a scanner using the given context.
+func (c *NessusClient) ListAgentGroupsContext(ctx context.Context, scannerID int64) ([]AgentGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/scanners/%d/agent-groups", scannerID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Groups []AgentGroup `json:"groups"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Groups, nil
+}
+
+// GetAgentGroup retrieves an agent group by ID
+func (c *NessusClient) GetAgentGroup(scannerID, groupID int64) (*AgentGroup, error) {
+	return c.GetAgentGroupContext(context.Background(), scannerID, groupID)
+}
+
+// GetAgentGroupContext retrieves an agent group by ID using the given context.
+func (c *NessusClient) GetAgentGroupContext(ctx context.Context, scannerID, groupID int64) (*AgentGroup, error) {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d", scannerID, groupID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &AgentGroup{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// CreateAgentGroup creates an agent group
+func (c *NessusClient) CreateAgentGroup(scannerID int64, name string) (*AgentGroup, error) {
+	return c.CreateAgentGroupContext(context.Background(), scannerID, name)
+}
+
+// CreateAgentGroupContext creates an agent group using the given context.
+func (c *NessusClient) CreateAgentGroupContext(ctx context.Context, scannerID int64, name string) (*AgentGroup, error) {
+	payload := map[string]string{"name": name}
+	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/scanners/%d/agent-groups", scannerID), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &AgentGroup{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// EditAgentGroup renames an agent group
+func (c *NessusClient) EditAgentGroup(scannerID, groupID int64, name string) error {
+	return c.EditAgentGroupContext(context.Background(), scannerID, groupID, name)
+}
+
+// EditAgentGroupContext renames an agent group using the given context.
+func (c *NessusClient) EditAgentGroupContext(ctx context.Context, scannerID, groupID int64, name string) error {
+	payload := map[string]string{"name": name}
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d", scannerID, groupID)
+	req, err := c.newRequest(ctx, http.MethodPut, path, payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// DeleteAgentGroup removes an agent group
+func (c *NessusClient) DeleteAgentGroup(scannerID, groupID int64) error {
+	return c.DeleteAgentGroupContext(context.Background(), scannerID, groupID)
+}
+
+// DeleteAgentGroupContext removes an agent group using the given context.
+func (c *NessusClient) DeleteAgentGroupContext(ctx context.Context, scannerID, groupID int64) error {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d", scannerID, groupID)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// AddAgentToGroup adds an agent to an agent group
+func (c *NessusClient) AddAgentToGroup(scannerID, groupID, agentID int64) error {
+	return c.AddAgentToGroupContext(context.Background(), scannerID, groupID, agentID)
+}
+
+// AddAgentToGroupContext adds an agent to an agent group using the given context.
+func (c *NessusClient) AddAgentToGroupContext(ctx context.Context, scannerID, groupID, agentID int64) error {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d/agents/%d", scannerID, groupID, agentID)
+	req, err := c.newRequest(ctx, http.MethodPut, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// RemoveAgentFromGroup removes an agent from an agent group
+func (c *NessusClient) RemoveAgentFromGroup(scannerID, groupID, agentID int64) error {
+	return c.RemoveAgentFromGroupContext(context.Background(), scannerID, groupID, agentID)
+}
+
+// RemoveAgentFromGroupContext removes an agent from an agent group using the given context.
+func (c *NessusClient) RemoveAgentFromGroupContext(ctx context.Context, scannerID, groupID, agentID int64) error {
+	path := fmt.Sprintf("/scanners/%d/agent-groups/%d/agents/%d", scannerID, groupID, agentID)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+func (c *NessusClient) agentBulkJob(ctx context.Context, path string, agentIDs []int64) (*AgentBulkJob, error) {
+	payload := map[string][]int64{"items": agentIDs}
+	req, err := c.newRequest(ctx, http.MethodPost, path, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &AgentBulkJob{}
+	err = c.performCallAndReadResponse(req, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (c *NessusClient) getAgentBulkJob(ctx context.Context, path string) (*AgentBulkJob, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &AgentBulkJob{}
+	err = c.performCallAndReadResponse(req, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
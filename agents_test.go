@@ -0,0 +1,57 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestListAgentsPagination(t *testing.T) {
+	const total = 2500
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/scanners/1/agents" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if f := r.URL.Query().Get("f"); f != "platform:match:LINUX" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+			var agents []Agent
+			for i := offset; i < total && i < offset+limit; i++ {
+				agents = append(agents, Agent{ID: int64(i)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"agents":     agents,
+				"pagination": Pagination{Total: total, Offset: offset, Limit: limit},
+			})
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	agents, err := c.ListAgents(1, &AgentListOptions{
+		Filters: []AgentFilter{{Field: "platform", Operator: "match", Value: "LINUX"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(agents) != total {
+		t.Fatalf("got: %d agents, expected: %d", len(agents), total)
+	}
+	for i, a := range agents {
+		if a.ID != int64(i) {
+			t.Fatalf("got agent: %d at position %d", a.ID, i)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package restuss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Audit log actions on scans
+const (
+	AuditActionScanCreate = "scan.create"
+	AuditActionScanLaunch = "scan.launch"
+	AuditActionScanStop   = "scan.stop"
+	AuditActionScanDelete = "scan.delete"
+)
+
+// AuditLogFilter restricts the events returned by the audit log. Empty
+// fields are ignored.
+type AuditLogFilter struct {
+	Since    time.Time
+	Until    time.Time
+	ActorID  string
+	Action   string
+	TargetID string
+}
+
+// AuditLogIterator iterates over audit log events, fetching them page by
+// page as the iteration advances:
+//
+//	it := c.AuditLog(ctx, restuss.AuditLogFilter{Action: restuss.AuditActionScanDelete})
+//	for it.Next() {
+//		ev := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type AuditLogIterator struct {
+	client *NessusClient
+	ctx    context.Context
+	filter AuditLogFilter
+
+	page  []AuditEvent
+	event AuditEvent
+	since time.Time
+	seen  map[string]bool
+	last  bool
+	err   error
+}
+
+// auditLogPageSize is the maximum number of events the audit log returns per call
+const auditLogPageSize = 5000
+
+// AuditLog returns an iterator over the audit log events matching the filter
+func (c *NessusClient) AuditLog(ctx context.Context, filter AuditLogFilter) *AuditLogIterator {
+	return &AuditLogIterator{client: c, ctx: ctx, filter: filter, since: filter.Since, seen: map[string]bool{}}
+}
+
+// Next advances to the next event, it returns false when there are no more
+// events or an error occurred.
+func (it *AuditLogIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.last || it.err != nil {
+			return false
+		}
+		it.err = it.fetch()
+	}
+	it.event, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Event returns the current event
+func (it *AuditLogIterator) Event() AuditEvent {
+	return it.event
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AuditLogIterator) Err() error {
+	return it.err
+}
+
+// fetch retrieves the next page. The audit log is not offset paginated, so
+// pages are requested with a date filter moving past the latest event
+// received, events already returned at the boundary are skipped. The events
+// of a page are not in any particular order, they are sorted by date.
+func (it *AuditLogIterator) fetch() error {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(auditLogPageSize))
+	if !it.since.IsZero() {
+		q.Add("f", "date.gt:"+it.since.UTC().Format(time.RFC3339))
+	}
+	if !it.filter.Until.IsZero() {
+		q.Add("f", "date.lt:"+it.filter.Until.UTC().Format(time.RFC3339))
+	}
+	if it.filter.ActorID != "" {
+		q.Add("f", "actor_id.match:"+it.filter.ActorID)
+	}
+	if it.filter.Action != "" {
+		q.Add("f", "action.match:"+it.filter.Action)
+	}
+	if it.filter.TargetID != "" {
+		q.Add("f", "target_id.match:"+it.filter.TargetID)
+	}
+
+	req, err := it.client.newRequest(it.ctx, http.MethodGet, "/audit-log/v1/events?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		Events []AuditEvent `json:"events"`
+	}
+	err = it.client.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return err
+	}
+
+	latest := it.since
+	seen := map[string]bool{}
+	for _, ev := range data.Events {
+		if ev.Received.After(latest) {
+			latest = ev.Received
+		}
+		// The date filter only has a precision of a second, the events
+		// returned before it were in previous pages or are filtered out.
+		skip := it.seen[ev.ID] || !it.since.IsZero() && !ev.Received.After(it.since)
+		seen[ev.ID] = true
+		if skip {
+			continue
+		}
+		it.page = append(it.page, ev)
+	}
+	sort.SliceStable(it.page, func(i, j int) bool {
+		if !it.page[i].Received.Equal(it.page[j].Received) {
+			return it.page[i].Received.Before(it.page[j].Received)
+		}
+		return it.page[i].ID < it.page[j].ID
+	})
+
+	if len(data.Events) < auditLogPageSize {
+		it.last = true
+		return nil
+	}
+	if !latest.After(it.since) {
+		return errors.New("Too many audit log events received at the same time to paginate")
+	}
+
+	// Only events received during the last second can be returned again.
+	it.since = latest.Add(-time.Second)
+	it.seen = map[string]bool{}
+	for _, ev := range data.Events {
+		if seen[ev.ID] && ev.Received.After(it.since) {
+			it.seen[ev.ID] = true
+		}
+	}
+	return nil
+}
+
+// ScanAuditEvents returns the audit log events targeting the given scan, for
+// instance with action AuditActionScanDelete to know who deleted it. The scan
+// is matched by ID or by UUID.
+func (c *NessusClient) ScanAuditEvents(scan *PersistedScan, filter AuditLogFilter) ([]AuditEvent, error) {
+	return c.ScanAuditEventsContext(context.Background(), scan, filter)
+}
+
+// ScanAuditEventsContext returns the audit log events targeting the given
+// scan using the given context. The TargetID of the filter is ignored.
+func (c *NessusClient) ScanAuditEventsContext(ctx context.Context, scan *PersistedScan, filter AuditLogFilter) ([]AuditEvent, error) {
+	ids := []string{strconv.FormatInt(scan.ID, 10)}
+	if scan.UUID != "" {
+		ids = append(ids, scan.UUID)
+	}
+
+	var events []AuditEvent
+	seen := map[string]bool{}
+	for _, id := range ids {
+		filter.TargetID = id
+		it := c.AuditLog(ctx, filter)
+		for it.Next() {
+			ev := it.Event()
+			// The target is matched as a substring by the API.
+			if ev.Target.ID == id && !seen[ev.ID] {
+				seen[ev.ID] = true
+				events = append(events, ev)
+			}
+		}
+		if it.Err() != nil {
+			return nil, it.Err()
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Received.Before(events[j].Received)
+	})
+
+	return events, nil
+}
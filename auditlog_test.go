@@ -0,0 +1,143 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newAuditLogServer returns a server of the audit log events, which answers
+// the earliest events matching the filters of a request, newest first, and
+// returns the requests received
+func newAuditLogServer(t *testing.T, events []AuditEvent) (*httptest.Server, func() []string) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/audit-log/v1/events" {
+				t.Errorf("got request to %s, expected the audit log events", r.URL.Path)
+			}
+			mu.Lock()
+			requests = append(requests, r.URL.RawQuery)
+			mu.Unlock()
+
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			var matching []AuditEvent
+			for _, ev := range events {
+				ok := true
+				for _, f := range r.URL.Query()["f"] {
+					name, value, _ := strings.Cut(f, ":")
+					switch name {
+					case "date.gt":
+						since, _ := time.Parse(time.RFC3339, value)
+						ok = ok && ev.Received.After(since)
+					case "target_id.match":
+						ok = ok && strings.Contains(ev.Target.ID, value)
+					default:
+						t.Errorf("got unexpected filter: %s", f)
+					}
+				}
+				if ok {
+					matching = append(matching, ev)
+				}
+			}
+			sort.Slice(matching, func(i, j int) bool {
+				return matching[i].Received.Before(matching[j].Received)
+			})
+			if len(matching) > limit {
+				matching = matching[:limit]
+			}
+			for i, j := 0, len(matching)-1; i < j; i, j = i+1, j-1 {
+				matching[i], matching[j] = matching[j], matching[i]
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"events": matching})
+		}))
+	return ts, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestAuditLogPagination(t *testing.T) {
+	const total = 12000
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]AuditEvent, total)
+	for i := range events {
+		// Several events per second, stored out of order.
+		n := (i * 7919) % total
+		events[i] = AuditEvent{
+			ID:       fmt.Sprintf("e-%05d", n),
+			Received: start.Add(time.Duration(n/4) * 250 * time.Millisecond),
+		}
+	}
+	ts, requests := newAuditLogServer(t, events)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	var got []AuditEvent
+	it := c.AuditLog(context.Background(), AuditLogFilter{})
+	for it.Next() {
+		got = append(got, it.Event())
+	}
+	if it.Err() != nil {
+		t.Fatalf("Unexpected error: %v", it.Err())
+	}
+
+	if n := len(requests()); n < 3 {
+		t.Fatalf("got %d requests, expected several pages", n)
+	}
+	if len(got) != total {
+		t.Fatalf("got: %d events, expected: %d", len(got), total)
+	}
+	for i, ev := range got {
+		if want := fmt.Sprintf("e-%05d", i); ev.ID != want {
+			t.Fatalf("got event: %s at position %d, expected: %s", ev.ID, i, want)
+		}
+	}
+}
+
+func TestScanAuditEvents(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []AuditEvent{
+		{ID: "e-1", Received: start.Add(3 * time.Second), Target: AuditTarget{ID: "scan-uuid"}},
+		{ID: "e-2", Received: start.Add(1 * time.Second), Target: AuditTarget{ID: "42"}},
+		{ID: "e-3", Received: start.Add(2 * time.Second), Target: AuditTarget{ID: "420"}},
+		{ID: "e-4", Received: start.Add(4 * time.Second), Target: AuditTarget{ID: "7"}},
+	}
+	ts, requests := newAuditLogServer(t, events)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	got, err := c.ScanAuditEvents(&PersistedScan{ID: 42, UUID: "scan-uuid"}, AuditLogFilter{TargetID: "7"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "e-2" || got[1].ID != "e-1" {
+		t.Fatalf("got events: %+v, expected: e-2 and e-1", got)
+	}
+	for _, q := range requests() {
+		if !strings.Contains(q, "target_id.match") {
+			t.Fatalf("got request: %s, expected a target filter", q)
+		}
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -38,6 +40,9 @@ type NessusClient struct {
 	auth       AuthProvider
 	url        string
 	httpClient *http.Client
+	metrics    *ClientMetrics
+	hooks      *Hooks
+	limiter    *RateLimiter
 }
 
 // NewClient returns a new NessusClient
@@ -73,7 +78,7 @@ func (c *NessusClient) GetScanTemplatesContext(ctx context.Context) ([]*ScanTemp
 	}
 	err = c.performCallAndReadResponse(req, &data)
 	if err != nil {
-		return nil, errors.New("Call failed: " + err.Error())
+		return nil, fmt.Errorf("Call failed: %w", err)
 	}
 	return data.Templates, nil
 }
@@ -99,6 +104,36 @@ func (c *NessusClient) LaunchScanContext(ctx context.Context, scanID int64) erro
 	return c.performCallAndReadResponse(req, nil)
 }
 
+// LaunchScanWithTargets launches the scan with the given scanID against
+// altTargets instead of its configured targets, or against its configured
+// targets when altTargets is empty, returning the UUID of the run
+func (c *NessusClient) LaunchScanWithTargets(scanID int64, altTargets []string) (string, error) {
+	return c.LaunchScanWithTargetsContext(context.Background(), scanID, altTargets)
+}
+
+// LaunchScanWithTargetsContext launches the scan with the given scanID
+// against altTargets using the given context, returning the UUID of the run
+func (c *NessusClient) LaunchScanWithTargetsContext(ctx context.Context, scanID int64, altTargets []string) (string, error) {
+	path := "/scans/" + strconv.FormatInt(scanID, 10) + "/launch"
+	body := struct {
+		AltTargets []string `json:"alt_targets,omitempty"`
+	}{altTargets}
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ScanUUID string `json:"scan_uuid"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.ScanUUID, nil
+}
+
 // StopScan stops the scan with the given scanID
 func (c *NessusClient) StopScan(scanID int64) error {
 	return c.StopScanContext(context.Background(), scanID)
@@ -115,6 +150,36 @@ func (c *NessusClient) StopScanContext(ctx context.Context, scanID int64) error
 	return c.performCallAndReadResponse(req, nil)
 }
 
+// PauseScan pauses the running scan with the given scanID
+func (c *NessusClient) PauseScan(scanID int64) error {
+	return c.PauseScanContext(context.Background(), scanID)
+}
+
+// PauseScanContext pauses the running scan with the given scanID and context.
+func (c *NessusClient) PauseScanContext(ctx context.Context, scanID int64) error {
+	path := "/scans/" + strconv.FormatInt(scanID, 10) + "/pause"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ResumeScan resumes the paused scan with the given scanID
+func (c *NessusClient) ResumeScan(scanID int64) error {
+	return c.ResumeScanContext(context.Background(), scanID)
+}
+
+// ResumeScanContext resumes the paused scan with the given scanID and context.
+func (c *NessusClient) ResumeScanContext(ctx context.Context, scanID int64) error {
+	path := "/scans/" + strconv.FormatInt(scanID, 10) + "/resume"
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
 // DeleteScan will remove the scan with the given scanID
 func (c *NessusClient) DeleteScan(scanID int64) error {
 	return c.DeleteScanContext(context.Background(), scanID)
@@ -131,6 +196,115 @@ func (c *NessusClient) DeleteScanContext(ctx context.Context, scanID int64) erro
 	return c.performCallAndReadResponse(req, nil)
 }
 
+// ExportScan requests an export of the scan with the given scanID, returning the export file ID
+func (c *NessusClient) ExportScan(scanID int64, export *ScanExport) (int64, error) {
+	return c.ExportScanContext(context.Background(), scanID, export)
+}
+
+// ExportScanContext requests an export of the scan with the given scanID using the given context,
+// returning the export file ID
+func (c *NessusClient) ExportScanContext(ctx context.Context, scanID int64, export *ScanExport) (int64, error) {
+	jsonBody, err := json.Marshal(export)
+	if err != nil {
+		return 0, errors.New("Unable to marshall request body" + err.Error())
+	}
+	path := fmt.Sprintf("/scans/%d/export", scanID)
+	if export.HistoryID != 0 {
+		path += fmt.Sprintf("?history_id=%d", export.HistoryID)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.url+path, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return 0, errors.New("Unable to create request object: " + err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		File int64 `json:"file"`
+	}
+	req = req.WithContext(ctx)
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.File, nil
+}
+
+// GetScanExportStatus returns the status of a scan export, "ready" once it can be downloaded
+func (c *NessusClient) GetScanExportStatus(scanID, fileID int64) (string, error) {
+	return c.GetScanExportStatusContext(context.Background(), scanID, fileID)
+}
+
+// GetScanExportStatusContext returns the status of a scan export using the given context
+func (c *NessusClient) GetScanExportStatusContext(ctx context.Context, scanID, fileID int64) (string, error) {
+	path := fmt.Sprintf("/scans/%d/export/%d/status", scanID, fileID)
+	req, err := http.NewRequest(http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return "", errors.New("Unable to create request object: " + err.Error())
+	}
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	req = req.WithContext(ctx)
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Status, nil
+}
+
+// WaitScanExport waits for a scan export to be ready to download, checking
+// its status every pollInterval, every 5 seconds when it is not positive.
+func (c *NessusClient) WaitScanExport(scanID, fileID int64, pollInterval time.Duration) error {
+	return c.WaitScanExportContext(context.Background(), scanID, fileID, pollInterval)
+}
+
+// WaitScanExportContext waits for a scan export to be ready to download using
+// the given context. An error is returned when the export ends with another
+// status, such as "error".
+func (c *NessusClient) WaitScanExportContext(ctx context.Context, scanID, fileID int64, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	for {
+		status, err := c.GetScanExportStatusContext(ctx, scanID, fileID)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case "ready":
+			return nil
+		case "loading", "processing":
+		default:
+			return fmt.Errorf("Export %d of scan %d ended with status: %s", fileID, scanID, status)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// DownloadScanExport writes a ready scan export to w
+func (c *NessusClient) DownloadScanExport(scanID, fileID int64, w io.Writer) error {
+	return c.DownloadScanExportContext(context.Background(), scanID, fileID, w)
+}
+
+// DownloadScanExportContext writes a ready scan export to w using the given context
+func (c *NessusClient) DownloadScanExportContext(ctx context.Context, scanID, fileID int64, w io.Writer) error {
+	path := fmt.Sprintf("/scans/%d/export/%d/download", scanID, fileID)
+	req, err := http.NewRequest(http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return errors.New("Unable to create request object: " + err.Error())
+	}
+	req = req.WithContext(ctx)
+	return c.performCallAndReadResponse(req, w)
+}
+
 // CreateScan creates a scan
 func (c *NessusClient) CreateScan(scan *Scan) (*PersistedScan, error) {
 	return c.CreateScanContext(context.Background(), scan)
@@ -168,6 +342,23 @@ func (c *NessusClient) GetScans(lastModificationDate int64) ([]*PersistedScan, e
 
 // GetScansContext get a list of scan matching the provided lastModificationDate (check Nessus documentation) and context.
 func (c *NessusClient) GetScansContext(ctx context.Context, lastModificationDate int64) ([]*PersistedScan, error) {
+	list, err := c.GetScanListContext(ctx, lastModificationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Scans, nil
+}
+
+// GetScanList get the scan listing, including folders and the server timestamp,
+// matching the provided lastModificationDate
+func (c *NessusClient) GetScanList(lastModificationDate int64) (*ScanList, error) {
+	return c.GetScanListContext(context.Background(), lastModificationDate)
+}
+
+// GetScanListContext get the scan listing, including folders and the server timestamp,
+// matching the provided lastModificationDate using the given context.
+func (c *NessusClient) GetScanListContext(ctx context.Context, lastModificationDate int64) (*ScanList, error) {
 	req, err := http.NewRequest(http.MethodGet, c.url+"/scans", nil)
 	if err != nil {
 		return nil, errors.New("Unable to create request object: " + err.Error())
@@ -179,16 +370,14 @@ func (c *NessusClient) GetScansContext(ctx context.Context, lastModificationDate
 		req.URL.RawQuery = q.Encode()
 	}
 
-	var data struct {
-		Scans []*PersistedScan `json:"scans"`
-	}
+	list := &ScanList{}
 	req = req.WithContext(ctx)
-	err = c.performCallAndReadResponse(req, &data)
+	err = c.performCallAndReadResponse(req, list)
 	if err != nil {
 		return nil, err
 	}
 
-	return data.Scans, nil
+	return list, nil
 }
 
 // GetScanByID retrieve a scan by ID
@@ -217,6 +406,31 @@ func (c *NessusClient) GetScanByIDContext(ctx context.Context, ID int64) (*ScanD
 	return scanDetail, nil
 }
 
+// GetScanHistory retrieves a past run of a scan by scan ID and history ID
+func (c *NessusClient) GetScanHistory(ID, historyID int64) (*ScanDetail, error) {
+	return c.GetScanHistoryContext(context.Background(), ID, historyID)
+}
+
+// GetScanHistoryContext retrieves a past run of a scan using the given context
+func (c *NessusClient) GetScanHistoryContext(ctx context.Context, ID, historyID int64) (*ScanDetail, error) {
+	path := fmt.Sprintf("/scans/%d?history_id=%d", ID, historyID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scanDetail := &ScanDetail{}
+	err = c.performCallAndReadResponse(req, scanDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	scanDetail.ID = ID
+	scanDetail.HistoryID = historyID
+
+	return scanDetail, nil
+}
+
 // GetPluginByID retrieves a plugin by ID
 func (c *NessusClient) GetPluginByID(ID int64) (*Plugin, error) {
 	return c.GetPluginByIDContext(context.Background(), ID)
@@ -305,20 +519,53 @@ func (c *NessusClient) GetPolicyByIDContext(ctx context.Context, ID int64) (*Pol
 	return p, nil
 }
 
+// ExportPolicy writes the policy with the given ID to w, in the .nessus format
+func (c *NessusClient) ExportPolicy(ID int64, w io.Writer) error {
+	return c.ExportPolicyContext(context.Background(), ID, w)
+}
+
+// ExportPolicyContext writes the policy with the given ID to w using the given context.
+func (c *NessusClient) ExportPolicyContext(ctx context.Context, ID int64, w io.Writer) error {
+	path := fmt.Sprintf("/policies/%d/export", ID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	// The export is XML.
+	req.Header.Del("Accept")
+	return c.performCallAndReadResponse(req, w)
+}
+
 // GetAssetByName returns an asset by its name. Returns an error if more than
 // one or none assets are matching.
 func (c *NessusClient) GetAssetByName(ctx context.Context, name string) (*Asset, error) {
+	return c.GetAssetByNameInNetwork(ctx, name, "")
+}
+
+// GetAssetByNameInNetwork returns an asset by its name within the network
+// with the given ID, all networks are searched when networkID is empty.
+// Returns an error if more than one or none assets are matching.
+func (c *NessusClient) GetAssetByNameInNetwork(ctx context.Context, name, networkID string) (*Asset, error) {
 	path := "/api/v3/assets/search"
 
+	conditions := []interface{}{
+		map[string]string{
+			"property": "name",
+			"operator": "eq",
+			"value":    name,
+		},
+	}
+	if networkID != "" {
+		conditions = append(conditions, map[string]string{
+			"property": "network.id",
+			"operator": "eq",
+			"value":    networkID,
+		})
+	}
+
 	payload := map[string]interface{}{
 		"filter": map[string]interface{}{
-			"and": []interface{}{
-				map[string]string{
-					"property": "name",
-					"operator": "eq",
-					"value":    name,
-				},
-			},
+			"and": conditions,
 		},
 	}
 
@@ -349,6 +596,9 @@ func (c *NessusClient) GetAssetByName(ctx context.Context, name string) (*Asset,
 		return nil, fmt.Errorf("No assets matching name: %v", name)
 	}
 	if count := len(result.Assets); count > 1 {
+		if networkID == "" {
+			return nil, fmt.Errorf("More than one asset matching name: %v (%d), try filtering by network", name, count)
+		}
 		return nil, fmt.Errorf("More than one asset matching name: %v (%d)", name, count)
 	}
 
@@ -358,37 +608,64 @@ func (c *NessusClient) GetAssetByName(ctx context.Context, name string) (*Asset,
 // GetFindingsByAssetName returns all the findings associated to an asset by
 // its name.
 func (c *NessusClient) GetFindingsByAssetName(ctx context.Context, name string) ([]Finding, error) {
+	return c.GetFindingsByAssetNameInNetwork(ctx, name, "")
+}
+
+// GetFindingsByAssetNameInNetwork returns all the findings associated to an
+// asset by its name within the network with the given ID, all networks are
+// searched when networkID is empty.
+func (c *NessusClient) GetFindingsByAssetNameInNetwork(ctx context.Context, name, networkID string) ([]Finding, error) {
+	return c.SearchFindingsByAssetName(ctx, name, &FindingsOptions{NetworkID: networkID})
+}
+
+// SearchFindingsByAssetName returns all the findings associated to an asset
+// by its name matching the given options, which can be nil.
+func (c *NessusClient) SearchFindingsByAssetName(ctx context.Context, name string, opts *FindingsOptions) ([]Finding, error) {
 	var findings []Finding
 	path := "/api/v3/findings/vulnerabilities/host/search"
 
+	if opts == nil {
+		opts = &FindingsOptions{}
+	}
+
+	conditions := []interface{}{
+		map[string]string{
+			"property": "asset.name",
+			"operator": "eq",
+			"value":    name,
+		},
+	}
+	if opts.NetworkID != "" {
+		conditions = append(conditions, map[string]string{
+			"property": "asset.network.id",
+			"operator": "eq",
+			"value":    opts.NetworkID,
+		})
+	}
+	if len(opts.States) > 0 {
+		var states []interface{}
+		for _, state := range opts.States {
+			states = append(states, map[string]string{
+				"property": "state",
+				"operator": "eq",
+				"value":    state,
+			})
+		}
+		conditions = append(conditions, map[string]interface{}{"or": states})
+	}
+
+	// NOTE: there are more fields available, by default we are using just
+	// those that are meaningful to us.
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = FindingFieldsDefault
+	}
+
 	payload := map[string]interface{}{
 		"filter": map[string]interface{}{
-			"and": []interface{}{
-				map[string]string{
-					"property": "asset.name",
-					"operator": "eq",
-					"value":    name,
-				},
-			},
-		},
-		// NOTE: there are more fields available, we are using just those that
-		// are meaningful to us.
-		"fields": []string{
-			"output",
-			"id",
-			"severity",
-			"port",
-			"protocol",
-			"service",
-			"plugin_id",
-			"name",
-			"description",
-			"synopsis",
-			"cvss3_base_score",
-			"cvss2_base_score",
-			"cwe",
-			"see_also",
+			"and": conditions,
 		},
+		"fields": fields,
 	}
 
 	jsonBody, err := json.Marshal(payload)
@@ -447,6 +724,78 @@ func (c *NessusClient) GetFindingsByAssetName(ctx context.Context, name string)
 	return findings, nil
 }
 
+// newRequest creates a request for the given path, encoding body as JSON
+// when not nil.
+func (c *NessusClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.New("Unable to marshall request body" + err.Error())
+		}
+		r = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, r)
+	if err != nil {
+		return nil, errors.New("Unable to create request object: " + err.Error())
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return req.WithContext(ctx), nil
+}
+
+// getAllPages retrieves every page of an offset paginated listing. The items
+// found under key are passed to add, which returns how many it decoded.
+func (c *NessusClient) getAllPages(ctx context.Context, path string, query url.Values, key string, add func(json.RawMessage) (int, error)) error {
+	const limit = 1000
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(limit))
+
+	for offset := 0; ; {
+		q.Set("offset", strconv.Itoa(offset))
+		req, err := c.newRequest(ctx, http.MethodGet, path+"?"+q.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		var page map[string]json.RawMessage
+		err = c.performCallAndReadResponse(req, &page)
+		if err != nil {
+			return err
+		}
+
+		n := 0
+		if raw, ok := page[key]; ok {
+			n, err = add(raw)
+			if err != nil {
+				return errors.New("Failed to read the response: " + err.Error())
+			}
+		}
+
+		var p Pagination
+		if raw, ok := page["pagination"]; ok {
+			err = json.Unmarshal(raw, &p)
+			if err != nil {
+				return errors.New("Failed to read the response: " + err.Error())
+			}
+		}
+
+		offset += n
+		if n == 0 || (p.Total > 0 && offset >= p.Total) || (p.Total == 0 && n < limit) {
+			return nil
+		}
+	}
+}
+
 func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interface{}) error {
 	// We implement backoff in all requests as the Tenable.io API
 	// is returning non-successful status codes inconsistently
@@ -475,12 +824,55 @@ func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interf
 	c.auth.AddAuthHeaders(req)
 
 	// Try 10 times then return an error.
+	const maxAttempts = 10
 	success := false
 	var res *http.Response
-	for i := 0; i < 10; i++ {
+	var lastStatus int
+	var lastBody []byte
+	// The event is nil, and the hooks aren't used, when the client has no
+	// hooks.
+	hooks := c.hooks
+	var endpoint string
+	if hooks != nil || c.limiter != nil {
+		endpoint = c.endpoint(req)
+	}
+	ev := c.newRequestEvent(req, endpoint)
+	// OnRequest can replace the context of an attempt, every attempt starts
+	// from the context of the call.
+	ctx := req.Context()
+	for i := 0; i < maxAttempts; i++ {
+		err = c.limiter.wait(ctx, endpoint)
+		if err != nil {
+			err = fmt.Errorf("Failed call: %w", err)
+			if ev != nil {
+				ev.Err = err
+				ev.run(hooks.OnError)
+			}
+			return err
+		}
+		if ev != nil {
+			ev.Attempt, ev.StatusCode, ev.Duration, ev.Wait = i+1, 0, 0, 0
+			ev.Context = ctx
+			ev.run(hooks.OnRequest)
+			req = req.WithContext(ev.Context)
+		}
+
+		start := time.Now()
 		res, err = c.httpClient.Do(req)
 		if err != nil {
-			return errors.New("Failed call: " + err.Error())
+			c.metrics.observe(req.Method, "error", time.Since(start))
+			err = errors.New("Failed call: " + err.Error())
+			if ev != nil {
+				ev.Duration, ev.Err = time.Since(start), err
+				ev.run(hooks.OnError)
+			}
+			return err
+		}
+		c.metrics.observe(req.Method, strconv.Itoa(res.StatusCode), time.Since(start))
+		c.limiter.observe(endpoint, res)
+		if ev != nil {
+			ev.StatusCode, ev.Duration = res.StatusCode, time.Since(start)
+			ev.run(hooks.OnResponse)
 		}
 
 		// We capture all non-2XX codes the same as the Tenable.io API returns
@@ -490,7 +882,7 @@ func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interf
 		// when an unknown request limit is exceeded.
 		if res.StatusCode >= 300 {
 			log.Printf("Request URL: %v", req.URL)
-			log.Printf("Request body: %v", string(reqBodyBytes))
+			log.Printf("Request body: %v", redactBody(reqBodyBytes))
 
 			buf, err := ioutil.ReadAll(res.Body)
 			if err != nil {
@@ -502,7 +894,11 @@ func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interf
 			}
 
 			log.Printf("Response status code: %v", res.StatusCode)
-			log.Printf("Response body: %v", string(buf))
+			log.Printf("Response body: %v", redactBody(buf))
+			lastStatus, lastBody = res.StatusCode, buf
+			if i == maxAttempts-1 {
+				break
+			}
 
 			waitTime := b.Duration()
 
@@ -526,7 +922,23 @@ func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interf
 				)
 			}
 
-			time.Sleep(waitTime)
+			c.metrics.retry()
+			if ev != nil {
+				ev.Wait = waitTime
+				ev.run(hooks.OnRetry)
+			}
+			t := time.NewTimer(waitTime)
+			select {
+			case <-req.Context().Done():
+				t.Stop()
+				err = fmt.Errorf("Failed call: %w", req.Context().Err())
+				if ev != nil {
+					ev.Err = err
+					ev.run(hooks.OnError)
+				}
+				return err
+			case <-t.C:
+			}
 			continue
 		}
 
@@ -544,13 +956,32 @@ func (c *NessusClient) performCallAndReadResponse(req *http.Request, data interf
 	}(res)
 
 	if !success {
-		return errors.New("Retry limit exceeded")
+		err = &RetryLimitError{StatusCode: lastStatus, Body: redactBody(lastBody)}
+	} else {
+		err = readResponse(res, data)
+	}
+	if err != nil && ev != nil {
+		ev.Err = err
+		ev.run(hooks.OnError)
+	}
+	return err
+}
+
+// readResponse reads the body of a successful response into data
+func readResponse(res *http.Response, data interface{}) error {
+	// Raw responses, such as export downloads, are copied as they are.
+	if w, ok := data.(io.Writer); ok {
+		_, err := io.Copy(w, res.Body)
+		if err != nil {
+			return errors.New("Failed to read the response: " + err.Error())
+		}
+		return nil
 	}
 
 	if data != nil {
 		d := json.NewDecoder(res.Body)
 
-		err = d.Decode(&data)
+		err := d.Decode(&data)
 		if err != nil {
 			return errors.New("Failed to read the response: " + err.Error())
 		}
@@ -1,10 +1,13 @@
 package restuss
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestPerformCallAndReadResponse(t *testing.T) {
@@ -72,3 +75,146 @@ func err2str(err error) string {
 	}
 	return ""
 }
+
+func TestHooks(t *testing.T) {
+	var exhausted []string
+	for i := 1; i <= 10; i++ {
+		exhausted = append(exhausted,
+			fmt.Sprintf("request scans.launch %d 0", i),
+			fmt.Sprintf("response scans.launch %d 429", i))
+		if i < 10 {
+			exhausted = append(exhausted, fmt.Sprintf("retry scans.launch %d 429", i))
+		}
+	}
+	exhausted = append(exhausted, "error scans.launch 10 429")
+
+	tests := []struct {
+		name     string
+		handler  func(attempt int, w http.ResponseWriter)
+		wantErr  bool
+		expected []string
+	}{
+		{
+			name: "retried",
+			handler: func(attempt int, w http.ResponseWriter) {
+				if attempt == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				fmt.Fprint(w, `{"scan_uuid": "u-42"}`)
+			},
+			expected: []string{
+				"request scans.launch 1 0",
+				"response scans.launch 1 502",
+				"retry scans.launch 1 502",
+				"request scans.launch 2 0",
+				"response scans.launch 2 200",
+			},
+		},
+		{
+			// The last attempt isn't retried, it fails the call.
+			name: "retries exhausted",
+			handler: func(attempt int, w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantErr:  true,
+			expected: exhausted,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				tc.handler(attempts, w)
+			}))
+			defer ts.Close()
+
+			c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+			if err != nil {
+				t.Fatalf("Error creating new client: %v", err)
+			}
+			var events []string
+			record := func(name string) func(e *RequestEvent) {
+				return func(e *RequestEvent) {
+					events = append(events, fmt.Sprintf("%s %s %d %d", name, e.Endpoint, e.Attempt, e.StatusCode))
+				}
+			}
+			type key struct{}
+			ctx := context.WithValue(context.Background(), key{}, "call")
+			onRequest := record("request")
+			c.SetHooks(&Hooks{
+				// Every attempt gets the context of the call, not the one the
+				// hook returned for the previous attempt.
+				OnRequest: func(e *RequestEvent) {
+					onRequest(e)
+					if e.Context != ctx {
+						t.Errorf("got context %v for attempt %d, expected the context of the call", e.Context, e.Attempt)
+					}
+					e.Context = context.WithValue(e.Context, key{}, e.Attempt)
+				},
+				OnResponse: record("response"),
+				OnRetry:    record("retry"),
+				OnError:    record("error"),
+			})
+
+			_, err = c.LaunchScanWithTargetsContext(ctx, 42, nil)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, expected error: %v", err, tc.wantErr)
+			}
+			if fmt.Sprint(events) != fmt.Sprint(tc.expected) {
+				t.Fatalf("got events %q, expected %q", events, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.LaunchScanWithTargetsContext(ctx, 42, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error: %v, expected the deadline exceeded", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("got call returning after %v, expected it to stop waiting at the deadline", d)
+	}
+}
+
+func TestEndpointName(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/scans", "scans.list"},
+		{http.MethodGet, "/scans/42", "scans.details"},
+		{http.MethodPost, "/scans/42/launch", "scans.launch"},
+		{http.MethodGet, "/scans/42/export/7/download", "scans.export_download"},
+		{http.MethodGet, "/credentials/types", "credentials.types"},
+		{http.MethodGet, "/credentials/0a1b", "credentials.details"},
+		{http.MethodGet, "/scanners/1/agents/_bulk/t-1", "agents.bulk_status"},
+		{http.MethodGet, "/scanners/1/agents/3", "agents.get"},
+		{http.MethodGet, "/server/status", "get.server"},
+	}
+	for _, tt := range tests {
+		if got := EndpointName(tt.method, tt.path); got != tt.want {
+			t.Errorf("got %q for %s %s, expected %q", got, tt.method, tt.path, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+// parseFlags parses the flags of a command, which must be followed by
+// exactly nargs arguments
+func parseFlags(fs *flag.FlagSet, args []string, nargs int) error {
+	err := fs.Parse(args)
+	if err != nil {
+		return usageError(fs.Name() + ": " + err.Error())
+	}
+	if fs.NArg() != nargs {
+		return usageError(fs.Name() + ": expected " + strconv.Itoa(nargs) + " arguments")
+	}
+	return nil
+}
+
+func templatesList(ctx context.Context, e *env, args []string) (*result, error) {
+	err := parseFlags(e.flags("templates list"), args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := e.client.GetScanTemplatesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: templates, header: []string{"UUID", "NAME", "TITLE", "AGENT"}}
+	for _, t := range templates {
+		res.add(t.UUID, t.Name, t.Title, t.IsAgent)
+	}
+	return res, nil
+}
+
+func policiesGet(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("policies get")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := e.client.GetPolicyByIDContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: p, header: []string{"ID", "UUID", "NAME"}}
+	res.add(p.ID, p.UUID, p.Settings.Name)
+	return res, nil
+}
+
+func policiesExport(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("policies export")
+	out := fs.String("out", "", "output file, standard output when empty")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, writeOutput(e, *out, func(w io.Writer) error {
+		return e.client.ExportPolicyContext(ctx, id, w)
+	})
+}
+
+func pluginsGet(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("plugins get")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := e.client.GetPluginByIDContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: p, header: []string{"ATTRIBUTE", "VALUE"}}
+	res.add("id", p.ID)
+	res.add("name", p.Name)
+	res.add("family", p.FamilyName)
+	for _, a := range p.Attributes {
+		res.add(a.Name, a.Value)
+	}
+	return res, nil
+}
+
+func assetsGet(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("assets get")
+	network := fs.String("network", "", "network ID, all networks when empty")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := e.client.GetAssetByNameInNetwork(ctx, fs.Arg(0), *network)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: a, header: []string{"ID", "NAME", "FQDNS", "SOURCES", "CREATED"}}
+	res.add(a.ID, a.Name, strings.Join(a.Fqdns, " "), strings.Join(a.Sources, " "), a.Created.Format(time.RFC3339))
+	return res, nil
+}
+
+var findingFieldPresets = map[string]restuss.FindingFields{
+	"minimal": restuss.FindingFieldsMinimal,
+	"default": restuss.FindingFieldsDefault,
+	"full":    restuss.FindingFieldsFull,
+}
+
+func findingsList(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("findings list")
+	network := fs.String("network", "", "network ID, all networks when empty")
+	states := fs.String("state", "", "comma separated finding states: open, reopened, fixed")
+	fields := fs.String("fields", "default", "fields preset: minimal, default or full")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	preset, ok := findingFieldPresets[*fields]
+	if !ok {
+		return nil, usageError("unknown fields preset: " + *fields)
+	}
+
+	opts := &restuss.FindingsOptions{NetworkID: *network, Fields: preset}
+	if *states != "" {
+		opts.States = strings.Split(*states, ",")
+	}
+	findings, err := e.client.SearchFindingsByAssetName(ctx, fs.Arg(0), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: findings, header: []string{"PLUGIN", "NAME", "SEVERITY", "PORT", "PROTOCOL", "STATE"}}
+	for _, f := range findings {
+		res.add(f.Definition.ID, f.Definition.Name, f.Severity, f.Port, f.Protocol, f.State)
+	}
+	return res, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/adevinta/restuss"
+)
+
+// config holds the connection settings, read from a JSON file and then
+// overridden by environment variables
+type config struct {
+	URL       string `json:"url"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Insecure  bool   `json:"insecure"`
+}
+
+// defaultConfigPath returns the path of the config file used when none is
+// given: $RESTUSS_CONFIG or restuss/config.json in the user config directory
+func defaultConfigPath() string {
+	if p := os.Getenv("RESTUSS_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "restuss", "config.json")
+}
+
+// loadConfig reads the config file at path, which may not exist when it's
+// the default one, and applies the environment variables
+func loadConfig(path string, explicit bool) (*config, error) {
+	cfg := &config{}
+	if path != "" {
+		buf, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			err = json.Unmarshal(buf, cfg)
+			if err != nil {
+				return nil, errors.New("Unable to parse config file " + path + ": " + err.Error())
+			}
+		case explicit || !os.IsNotExist(err):
+			return nil, errors.New("Unable to read config file: " + err.Error())
+		}
+	}
+
+	env := map[string]*string{
+		"RESTUSS_URL":        &cfg.URL,
+		"RESTUSS_ACCESS_KEY": &cfg.AccessKey,
+		"RESTUSS_SECRET_KEY": &cfg.SecretKey,
+		"RESTUSS_USERNAME":   &cfg.Username,
+		"RESTUSS_PASSWORD":   &cfg.Password,
+	}
+	for name, field := range env {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+		}
+	}
+	if v := os.Getenv("RESTUSS_INSECURE"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("Invalid RESTUSS_INSECURE: " + v)
+		}
+		cfg.Insecure = insecure
+	}
+
+	if cfg.URL == "" {
+		return nil, errors.New("No URL configured, set RESTUSS_URL or url in the config file")
+	}
+	return cfg, nil
+}
+
+// noCredentialsError is returned when neither API keys nor username and
+// password are configured
+type noCredentialsError struct{}
+
+func (noCredentialsError) Error() string {
+	return "No credentials configured, set RESTUSS_ACCESS_KEY and RESTUSS_SECRET_KEY or RESTUSS_USERNAME and RESTUSS_PASSWORD"
+}
+
+// client returns a client authenticated with API keys if configured, or
+// with username and password otherwise
+func (cfg *config) client() (*restuss.NessusClient, error) {
+	var auth restuss.AuthProvider
+	switch {
+	case cfg.AccessKey != "" && cfg.SecretKey != "":
+		auth = restuss.NewKeyAuthProvider(cfg.AccessKey, cfg.SecretKey)
+	case cfg.Username != "" && cfg.Password != "":
+		auth = restuss.NewBasicAuthProvider(cfg.Username, cfg.Password)
+	default:
+		return nil, noCredentialsError{}
+	}
+	return restuss.NewClient(auth, cfg.URL, cfg.Insecure)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+// gateFailedError is returned when findings violate the gate policy
+type gateFailedError struct{}
+
+func (gateFailedError) Error() string {
+	return "gate policy violated"
+}
+
+// gateFlags holds the flags common to the gate commands
+type gateFlags struct {
+	policy *string
+	junit  *string
+}
+
+func newGateFlags(fs *flag.FlagSet) *gateFlags {
+	return &gateFlags{
+		policy: fs.String("policy", "", "gate policy file"),
+		junit:  fs.String("junit", "", "JUnit XML report file"),
+	}
+}
+
+func (g *gateFlags) loadPolicy() (*restuss.GatePolicy, error) {
+	if *g.policy == "" {
+		return nil, usageError("a gate policy is required, set -policy")
+	}
+	f, err := os.Open(*g.policy)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return restuss.LoadGatePolicy(f)
+}
+
+// report prints the summary and writes the JUnit report of the result
+func (g *gateFlags) report(e *env, r *restuss.GateResult) error {
+	err := r.WriteSummary(e.stdout)
+	if err != nil {
+		return err
+	}
+	if *g.junit != "" {
+		f, err := os.Create(*g.junit)
+		if err != nil {
+			return err
+		}
+		err = r.WriteJUnit(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !r.Passed() {
+		return gateFailedError{}
+	}
+	return nil
+}
+
+func gateRun(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("gate run")
+	g := newGateFlags(fs)
+	targets := fs.String("targets", "", "comma separated targets replacing those of the scan")
+	interval := fs.Duration("interval", 30*time.Second, "polling interval")
+	timeout := fs.Duration("timeout", 0, "maximum time to wait, no limit when zero")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	policy, err := g.loadPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	opts := restuss.GateOptions{PollInterval: *interval}
+	if *targets != "" {
+		opts.AltTargets = strings.Split(*targets, ",")
+	}
+	r, err := e.client.RunGateContext(ctx, id, policy, opts)
+	if err != nil {
+		return nil, err
+	}
+	return nil, g.report(e, r)
+}
+
+func gateCheck(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("gate check")
+	g := newGateFlags(fs)
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := g.loadPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	findings, err := restuss.ParseNessus(f)
+	if err != nil {
+		return nil, err
+	}
+	return nil, g.report(e, policy.Evaluate(findings, time.Now()))
+}
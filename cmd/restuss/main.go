@@ -0,0 +1,220 @@
+// Command restuss runs day-to-day operations against Nessus and Tenable.io.
+//
+// Usage:
+//
+//	restuss [-config file] [-o table|json|csv] <resource> <action> [flags] [args]
+//
+// The URL and credentials are read from the config file, a JSON object with
+// the url, access_key, secret_key, username, password and insecure keys, and
+// are overridden by the RESTUSS_URL, RESTUSS_ACCESS_KEY, RESTUSS_SECRET_KEY,
+// RESTUSS_USERNAME, RESTUSS_PASSWORD and RESTUSS_INSECURE environment
+// variables. The config file defaults to $RESTUSS_CONFIG, or
+// restuss/config.json in the user config directory.
+//
+// Exit codes:
+//
+//	0 success
+//	1 failure
+//	2 invalid usage
+//	3 missing or insufficient credentials
+//	4 resource not found
+//	5 rate limited or server failure, trying again later may succeed
+//	6 the scan waited for didn't complete
+//	7 the findings violate the gate policy
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/adevinta/restuss"
+)
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitTemporary    = 5
+	exitScanFailed   = 6
+	exitGateFailed   = 7
+)
+
+// usageError is returned for invalid command lines
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// env is what commands run with
+type env struct {
+	client *restuss.NessusClient
+	stdout io.Writer
+	format string
+}
+
+// flags returns a flag set for a command, which also accepts the output flag
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&e.format, "o", e.format, "output format: table, json or csv")
+	return fs
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, e *env, args []string) (*result, error)
+}
+
+var commands = map[string]map[string]command{
+	"scans": {
+		"list":   {"[-since unix-time]", scansList},
+		"create": {"-template uuid -name name -targets targets [-policy id] [-scanner id] [-launch]", scansCreate},
+		"launch": {"scan-id", scanAction((*restuss.NessusClient).LaunchScanContext)},
+		"stop":   {"scan-id", scanAction((*restuss.NessusClient).StopScanContext)},
+		"pause":  {"scan-id", scanAction((*restuss.NessusClient).PauseScanContext)},
+		"resume": {"scan-id", scanAction((*restuss.NessusClient).ResumeScanContext)},
+		"delete": {"scan-id", scanAction((*restuss.NessusClient).DeleteScanContext)},
+		"wait":   {"[-interval duration] [-timeout duration] scan-id", scansWait},
+		"export": {"[-format nessus|csv|html|pdf] [-chapters chapters] [-history id] [-out file] scan-id", scansExport},
+	},
+	"templates": {
+		"list": {"", templatesList},
+	},
+	"policies": {
+		"get":    {"policy-id", policiesGet},
+		"export": {"[-out file] policy-id", policiesExport},
+	},
+	"plugins": {
+		"get": {"plugin-id", pluginsGet},
+	},
+	"assets": {
+		"get": {"[-network id] name", assetsGet},
+	},
+	"findings": {
+		"list": {"[-network id] [-state states] [-fields minimal|default|full] asset-name", findingsList},
+	},
+	"gate": {
+		"run":   {"-policy file [-junit file] [-targets targets] [-interval duration] [-timeout duration] scan-id", gateRun},
+		"check": {"-policy file [-junit file] file.nessus", gateCheck},
+	},
+}
+
+// offline lists the commands which don't call the API, and so run without
+// configuration
+var offline = map[string]bool{
+	"gate check": true,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, format: formatTable}
+	fs := e.flags("restuss")
+	configPath := fs.String("config", "", "config file")
+	err := fs.Parse(args)
+	if err != nil {
+		return fail(stderr, usageError(err.Error()))
+	}
+
+	args = fs.Args()
+	if len(args) < 2 {
+		return fail(stderr, usageError("missing command"))
+	}
+	cmd, ok := commands[args[0]][args[1]]
+	if !ok {
+		return fail(stderr, usageError("unknown command: "+strings.Join(args[:2], " ")))
+	}
+
+	if !offline[args[0]+" "+args[1]] {
+		path, explicit := *configPath, *configPath != ""
+		if !explicit {
+			path = defaultConfigPath()
+		}
+		cfg, err := loadConfig(path, explicit)
+		if err != nil {
+			return fail(stderr, err)
+		}
+		e.client, err = cfg.client()
+		if err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	res, err := cmd.run(ctx, e, args[2:])
+	if err != nil {
+		return fail(stderr, err)
+	}
+	if res != nil {
+		err = render(stdout, e.format, res)
+		if err != nil {
+			return fail(stderr, err)
+		}
+	}
+	return exitOK
+}
+
+// fail prints the error and returns the exit code matching it
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintln(stderr, "restuss:", err)
+
+	var rle *restuss.RetryLimitError
+	if errors.As(err, &rle) && rle.Body != "" {
+		fmt.Fprintf(stderr, "restuss: last response (%d): %s\n", rle.StatusCode, rle.Body)
+	}
+
+	var usage usageError
+	var scanFailed *restuss.ScanFailedError
+	var gateFailed gateFailedError
+	var noCredentials noCredentialsError
+	switch {
+	case errors.As(err, &usage):
+		printUsage(stderr)
+		return exitUsage
+	case errors.As(err, &scanFailed):
+		return exitScanFailed
+	case errors.As(err, &gateFailed):
+		return exitGateFailed
+	case errors.As(err, &noCredentials), restuss.IsUnauthorized(err):
+		return exitUnauthorized
+	case restuss.IsNotFound(err):
+		return exitNotFound
+	case restuss.IsTemporary(err):
+		return exitTemporary
+	}
+	return exitError
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: restuss [-config file] [-o table|json|csv] <resource> <action> [flags] [args]")
+	resources := make([]string, 0, len(commands))
+	for r := range commands {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	for _, r := range resources {
+		actions := make([]string, 0, len(commands[r]))
+		for a := range commands[r] {
+			actions = append(actions, a)
+		}
+		sort.Strings(actions)
+		for _, a := range actions {
+			fmt.Fprintf(w, "  %s %s %s\n", r, a, commands[r][a].usage)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adevinta/restuss"
+)
+
+func TestRunScansList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scans" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"scans": [{"id": 7, "uuid": "u-7", "name": "staging", "status": "completed"}]}`)
+	}))
+	defer ts.Close()
+
+	t.Setenv("RESTUSS_CONFIG", "")
+	t.Setenv("RESTUSS_URL", ts.URL)
+	t.Setenv("RESTUSS_ACCESS_KEY", "access")
+	t.Setenv("RESTUSS_SECRET_KEY", "secret")
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-config", "", "-o", "csv", "scans", "list"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("got exit code %d, expected %d: %s", code, exitOK, stderr.String())
+	}
+
+	expected := "ID,UUID,NAME,STATUS,LAST MODIFIED\n7,u-7,staging,completed,\n"
+	if stdout.String() != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", stdout.String(), expected)
+	}
+}
+
+func TestFailExitCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{usageError("missing command"), exitUsage},
+		{&restuss.RetryLimitError{StatusCode: http.StatusForbidden}, exitUnauthorized},
+		{noCredentialsError{}, exitUnauthorized},
+		{&restuss.RetryLimitError{StatusCode: http.StatusNotFound}, exitNotFound},
+		{&restuss.RetryLimitError{StatusCode: http.StatusTooManyRequests}, exitTemporary},
+		{fmt.Errorf("Call failed: %w", &restuss.RetryLimitError{StatusCode: http.StatusBadGateway}), exitTemporary},
+		{&restuss.ScanFailedError{ScanID: 1, Status: "aborted"}, exitScanFailed},
+		{fmt.Errorf("Gate failed: %w", &restuss.ScanFailedError{ScanID: 1, Status: "canceled"}), exitScanFailed},
+		{gateFailedError{}, exitGateFailed},
+		{io.ErrUnexpectedEOF, exitError},
+	}
+	for _, tt := range tests {
+		if code := fail(io.Discard, tt.err); code != tt.code {
+			t.Errorf("got exit code %d for %v, expected %d", code, tt.err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+func scansList(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("scans list")
+	since := fs.Int64("since", 0, "only list scans modified after this unix time")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	scans, err := e.client.GetScansContext(ctx, *since)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{value: scans, header: []string{"ID", "UUID", "NAME", "STATUS", "LAST MODIFIED"}}
+	for _, s := range scans {
+		res.add(s.ID, s.UUID, s.Name, s.Status, formatUnix(s.LastModificationDate))
+	}
+	return res, nil
+}
+
+func scansCreate(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("scans create")
+	template := fs.String("template", "", "scan template UUID")
+	name := fs.String("name", "", "scan name")
+	targets := fs.String("targets", "", "comma separated targets")
+	policy := fs.Int64("policy", 0, "policy ID")
+	scanner := fs.String("scanner", "", "scanner ID")
+	launch := fs.Bool("launch", false, "launch the scan once created")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if *template == "" || *name == "" || *targets == "" {
+		return nil, usageError("scans create requires -template, -name and -targets")
+	}
+
+	scan, err := e.client.CreateScanContext(ctx, &restuss.Scan{
+		TemplateUUID: *template,
+		Settings: restuss.ScanSettings{
+			Name:      *name,
+			Enabled:   true,
+			Targets:   *targets,
+			PolicyID:  *policy,
+			ScannerID: *scanner,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if *launch {
+		err = e.client.LaunchScanContext(ctx, scan.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &result{value: scan, header: []string{"ID", "UUID", "NAME"}}
+	res.add(scan.ID, scan.UUID, scan.Name)
+	return res, nil
+}
+
+// scanAction returns a command calling action on the scan given as argument
+func scanAction(action func(*restuss.NessusClient, context.Context, int64) error) func(context.Context, *env, []string) (*result, error) {
+	return func(ctx context.Context, e *env, args []string) (*result, error) {
+		fs := e.flags("scan action")
+		err := parseFlags(fs, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		id, err := parseID(fs.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		return nil, action(e.client, ctx, id)
+	}
+}
+
+func scansWait(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("scans wait")
+	interval := fs.Duration("interval", 30*time.Second, "polling interval")
+	timeout := fs.Duration("timeout", 0, "maximum time to wait, no limit when zero")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	for {
+		detail, err := e.client.GetScanByIDContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch detail.Info.Status {
+		case "completed", "imported":
+			res := &result{value: detail.Info, header: []string{"ID", "NAME", "STATUS", "HOSTS", "VULNERABILITIES"}}
+			res.add(id, detail.Info.Name, detail.Info.Status, len(detail.Hosts), len(detail.Vulnerabilities))
+			return res, nil
+		case "canceled", "aborted":
+			return nil, &restuss.ScanFailedError{ScanID: id, Status: detail.Info.Status}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func scansExport(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := e.flags("scans export")
+	format := fs.String("format", "nessus", "export format: nessus, csv, html or pdf")
+	chapters := fs.String("chapters", "", "chapters of html and pdf exports, e.g. vuln_hosts_summary")
+	history := fs.Int64("history", 0, "history ID of the run to export, the last one when zero")
+	out := fs.String("out", "", "output file, standard output when empty")
+	interval := fs.Duration("interval", 5*time.Second, "polling interval of the export status")
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	fileID, err := e.client.ExportScanContext(ctx, id, &restuss.ScanExport{
+		Format:    *format,
+		Chapters:  *chapters,
+		HistoryID: *history,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = e.client.WaitScanExportContext(ctx, id, fileID, *interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, writeOutput(e, *out, func(w io.Writer) error {
+		return e.client.DownloadScanExportContext(ctx, id, fileID, w)
+	})
+}
+
+// writeOutput calls write with the given file, or standard output when path
+// is empty
+func writeOutput(e *env, path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(e.stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func formatUnix(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, usageError("invalid ID: " + s)
+	}
+	return id, nil
+}
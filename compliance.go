@@ -0,0 +1,305 @@
+package restuss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ExportCompliance starts a compliance export job, returning its UUID
+func (c *NessusClient) ExportCompliance(export *ComplianceExportRequest) (string, error) {
+	return c.ExportComplianceContext(context.Background(), export)
+}
+
+// ExportComplianceContext starts a compliance export job using the given context, returning its UUID
+func (c *NessusClient) ExportComplianceContext(ctx context.Context, export *ComplianceExportRequest) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/compliance/export", export)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ExportUUID string `json:"export_uuid"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.ExportUUID, nil
+}
+
+// GetComplianceExportStatus returns the status of a compliance export job
+func (c *NessusClient) GetComplianceExportStatus(exportUUID string) (*ComplianceExportStatus, error) {
+	return c.GetComplianceExportStatusContext(context.Background(), exportUUID)
+}
+
+// GetComplianceExportStatusContext returns the status of a compliance export job using the given context.
+func (c *NessusClient) GetComplianceExportStatusContext(ctx context.Context, exportUUID string) (*ComplianceExportStatus, error) {
+	path := "/compliance/export/" + url.PathEscape(exportUUID) + "/status"
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ComplianceExportStatus{}
+	err = c.performCallAndReadResponse(req, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// DownloadComplianceChunk returns the findings of an available chunk of a compliance export
+func (c *NessusClient) DownloadComplianceChunk(exportUUID string, chunkID int) ([]ComplianceFinding, error) {
+	return c.DownloadComplianceChunkContext(context.Background(), exportUUID, chunkID)
+}
+
+// DownloadComplianceChunkContext returns the findings of an available chunk
+// of a compliance export using the given context.
+func (c *NessusClient) DownloadComplianceChunkContext(ctx context.Context, exportUUID string, chunkID int) ([]ComplianceFinding, error) {
+	path := fmt.Sprintf("/compliance/export/%s/chunks/%d", url.PathEscape(exportUUID), chunkID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []ComplianceFinding
+	err = c.performCallAndReadResponse(req, &findings)
+	if err != nil {
+		return nil, err
+	}
+
+	return findings, nil
+}
+
+// ComplianceFindings runs a compliance export and passes the findings of
+// each chunk to handle as soon as the chunk is available, so findings are
+// never all held in memory. The export status is checked every pollInterval,
+// every 5 seconds when it is not positive.
+func (c *NessusClient) ComplianceFindings(export *ComplianceExportRequest, pollInterval time.Duration, handle func([]ComplianceFinding) error) error {
+	return c.ComplianceFindingsContext(context.Background(), export, pollInterval, handle)
+}
+
+// ComplianceFindingsContext runs a compliance export and passes the findings
+// of each chunk to handle using the given context.
+func (c *NessusClient) ComplianceFindingsContext(ctx context.Context, export *ComplianceExportRequest, pollInterval time.Duration, handle func([]ComplianceFinding) error) error {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	exportUUID, err := c.ExportComplianceContext(ctx, export)
+	if err != nil {
+		return err
+	}
+
+	done := map[int]bool{}
+	for {
+		status, err := c.GetComplianceExportStatusContext(ctx, exportUUID)
+		if err != nil {
+			return err
+		}
+
+		for _, chunk := range status.ChunksAvailable {
+			if done[chunk] {
+				continue
+			}
+			findings, err := c.DownloadComplianceChunkContext(ctx, exportUUID, chunk)
+			if err != nil {
+				return err
+			}
+			err = handle(findings)
+			if err != nil {
+				return err
+			}
+			done[chunk] = true
+		}
+
+		switch status.Status {
+		case "FINISHED":
+			return nil
+		case "ERROR", "CANCELLED":
+			return errors.New("Compliance export " + exportUUID + " ended with status: " + status.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// ComplianceFindings returns the compliance results of the scan as
+// ComplianceFinding, summed up over the hosts: only the check name, plugin
+// ID and status are known from the scan details. ScanComplianceFindings
+// returns the results of each host with their actual and expected values.
+func (d *ScanDetail) ComplianceFindings() []ComplianceFinding {
+	findings := make([]ComplianceFinding, 0, len(d.Compliance))
+	for _, c := range d.Compliance {
+		findings = append(findings, ComplianceFinding{
+			PluginID:  c.PluginID,
+			CheckName: c.PluginName,
+			Status:    ComplianceStatus(c.Severity),
+		})
+	}
+	return findings
+}
+
+// ScanComplianceFindings returns the compliance results of each host of the
+// CompHosts of the scan details, read from the output of their checks.
+func (c *NessusClient) ScanComplianceFindings(d *ScanDetail) ([]ComplianceFinding, error) {
+	return c.ScanComplianceFindingsContext(context.Background(), d)
+}
+
+// ScanComplianceFindingsContext returns the compliance results of each host
+// of the CompHosts of the scan details using the given context.
+func (c *NessusClient) ScanComplianceFindingsContext(ctx context.Context, d *ScanDetail) ([]ComplianceFinding, error) {
+	var query string
+	if d.HistoryID != 0 {
+		query = fmt.Sprintf("?history_id=%d", d.HistoryID)
+	}
+
+	var findings []ComplianceFinding
+	for _, h := range d.CompHosts {
+		req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/scans/%d/hosts/%d%s", d.ID, h.ID, query), nil)
+		if err != nil {
+			return nil, err
+		}
+		var host struct {
+			Compliance []Vulnerability `json:"compliance"`
+		}
+		err = c.performCallAndReadResponse(req, &host)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, check := range host.Compliance {
+			f := ComplianceFinding{
+				Hostname:  h.Hostname,
+				PluginID:  check.PluginID,
+				CheckName: check.PluginName,
+				Status:    ComplianceStatus(check.Severity),
+			}
+			path := fmt.Sprintf("/scans/%d/hosts/%d/plugins/%d%s", d.ID, h.ID, check.PluginID, query)
+			req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+			if err != nil {
+				return nil, err
+			}
+			output := &PluginOutputResponse{}
+			err = c.performCallAndReadResponse(req, output)
+			if err != nil {
+				return nil, err
+			}
+			if len(output.Output) > 0 {
+				parseComplianceOutput(output.Output[0].Output, &f)
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+// complianceSections maps the headings of the output of a compliance check
+// to the field of the finding holding the text below them.
+var complianceSections = map[string]func(*ComplianceFinding) *string{
+	"Solution:":     func(f *ComplianceFinding) *string { return &f.Solution },
+	"See Also:":     func(f *ComplianceFinding) *string { return &f.SeeAlso },
+	"Policy Value:": func(f *ComplianceFinding) *string { return &f.ExpectedValue },
+	"Actual Value:": func(f *ComplianceFinding) *string { return &f.ActualValue },
+	"Reference:":    nil,
+}
+
+// parseComplianceOutput fills the finding with the result of a compliance
+// check as Nessus outputs it:
+//
+//	"1.1.1 Ensure mounting of cramfs filesystems is disabled": [FAILED]
+//
+//	The cramfs filesystem type is ...
+//
+//	Solution:
+//	...
+//
+//	Reference:
+//	800-53|CM-7,CSCv7|5.1
+//
+//	Policy Value:
+//	...
+//
+//	Actual Value:
+//	...
+func parseComplianceOutput(output string, f *ComplianceFinding) {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 {
+		header := strings.TrimSpace(lines[0])
+		i := strings.LastIndex(header, ":")
+		status := strings.TrimSpace(header[i+1:])
+		if i > 0 && strings.HasPrefix(status, "[") && strings.HasSuffix(status, "]") {
+			f.CheckName = strings.Trim(strings.TrimSpace(header[:i]), `"`)
+			f.Status = strings.ToUpper(strings.Trim(status, "[]"))
+			lines = lines[1:]
+		}
+	}
+
+	var (
+		heading string
+		text    []string
+	)
+	flush := func() {
+		value := strings.TrimSpace(strings.Join(text, "\n"))
+		switch {
+		case heading == "":
+			f.CheckInfo = value
+		case heading == "Reference:":
+			f.Reference = parseComplianceReferences(value)
+		default:
+			*complianceSections[heading](f) = value
+		}
+		text = nil
+	}
+	for _, line := range lines {
+		if _, ok := complianceSections[strings.TrimSpace(line)]; ok {
+			flush()
+			heading = strings.TrimSpace(line)
+			continue
+		}
+		text = append(text, line)
+	}
+	flush()
+}
+
+// parseComplianceReferences parses the comma separated framework|control
+// references of a compliance check.
+func parseComplianceReferences(value string) []ComplianceReference {
+	var refs []ComplianceReference
+	for _, ref := range strings.Split(value, ",") {
+		framework, control, ok := strings.Cut(strings.TrimSpace(ref), "|")
+		if !ok {
+			continue
+		}
+		refs = append(refs, ComplianceReference{Framework: framework, Control: control})
+	}
+	return refs
+}
+
+// ComplianceStatus maps the severity Nessus reports for a compliance check to
+// its result: informational checks passed, medium ones raised a warning and
+// high ones failed.
+func ComplianceStatus(severity int64) string {
+	switch {
+	case severity <= 0:
+		return CompliancePassed
+	case severity <= 2:
+		return ComplianceWarning
+	default:
+		return ComplianceFailed
+	}
+}
@@ -0,0 +1,146 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestComplianceFindings(t *testing.T) {
+	// The chunks available at each status check.
+	statuses := []ComplianceExportStatus{
+		{Status: "PROCESSING"},
+		{Status: "PROCESSING", ChunksAvailable: []int{1}},
+		{Status: "FINISHED", ChunksAvailable: []int{1, 2}, TotalChunks: 2},
+	}
+	chunks := map[string][]ComplianceFinding{
+		"/compliance/export/e-1/chunks/1": {{AssetUUID: "a-1"}, {AssetUUID: "a-2"}},
+		"/compliance/export/e-1/chunks/2": {{AssetUUID: "a-3"}},
+	}
+
+	var (
+		mu         sync.Mutex
+		export     ComplianceExportRequest
+		checks     int
+		downloaded []string
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/compliance/export":
+				_ = json.NewDecoder(r.Body).Decode(&export)
+				_, _ = w.Write([]byte(`{"export_uuid":"e-1"}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/compliance/export/e-1/status":
+				status := statuses[checks]
+				if checks < len(statuses)-1 {
+					checks++
+				}
+				_ = json.NewEncoder(w).Encode(status)
+			default:
+				findings, ok := chunks[r.URL.Path]
+				if !ok {
+					t.Errorf("got unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				downloaded = append(downloaded, r.URL.Path)
+				_ = json.NewEncoder(w).Encode(findings)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	var handled [][]ComplianceFinding
+	req := &ComplianceExportRequest{NumFindings: 2, Assets: []string{"a-1"}}
+	err = c.ComplianceFindings(req, time.Millisecond, func(findings []ComplianceFinding) error {
+		handled = append(handled, findings)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(&export, req) {
+		t.Fatalf("got export request: %+v, expected: %+v", export, *req)
+	}
+	want := []string{"/compliance/export/e-1/chunks/1", "/compliance/export/e-1/chunks/2"}
+	if !reflect.DeepEqual(downloaded, want) {
+		t.Fatalf("got chunks downloaded: %v, expected each once: %v", downloaded, want)
+	}
+	if len(handled) != 2 || len(handled[0]) != 2 || handled[1][0].AssetUUID != "a-3" {
+		t.Fatalf("got findings handled: %+v", handled)
+	}
+}
+
+func TestScanComplianceFindings(t *testing.T) {
+	output := `"1.1.1 Ensure mounting of cramfs filesystems is disabled": [FAILED]
+
+The cramfs filesystem type is a compressed read-only Linux filesystem.
+
+Solution:
+Edit or create a file in the /etc/modprobe.d/ directory.
+
+See Also:
+https://workbench.cisecurity.org/files/2662
+
+Reference:
+800-53|CM-7,CSCv7|5.1
+
+Policy Value:
+install /bin/true
+
+Actual Value:
+The command returned :
+
+install cramfs
+`
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("history_id") != "9" {
+				t.Errorf("got request: %s, expected the history 9", r.URL)
+			}
+			switch r.URL.Path {
+			case "/scans/5/hosts/2":
+				_, _ = w.Write([]byte(`{"compliance":[{"plugin_id":1001,"plugin_name":"1.1.1 Ensure mounting of cramfs filesystems is disabled","severity":3}]}`))
+			case "/scans/5/hosts/2/plugins/1001":
+				_ = json.NewEncoder(w).Encode(PluginOutputResponse{Output: []PluginOutput{{Output: output}}})
+			default:
+				t.Errorf("got unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	detail := &ScanDetail{ID: 5, HistoryID: 9, CompHosts: []Host{{ID: 2, Hostname: "web.example.com"}}}
+	findings, err := c.ScanComplianceFindings(detail)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []ComplianceFinding{{
+		Hostname:      "web.example.com",
+		Status:        ComplianceFailed,
+		PluginID:      1001,
+		CheckName:     "1.1.1 Ensure mounting of cramfs filesystems is disabled",
+		CheckInfo:     "The cramfs filesystem type is a compressed read-only Linux filesystem.",
+		ActualValue:   "The command returned :\n\ninstall cramfs",
+		ExpectedValue: "install /bin/true",
+		Solution:      "Edit or create a file in the /etc/modprobe.d/ directory.",
+		SeeAlso:       "https://workbench.cisecurity.org/files/2662",
+		Reference:     []ComplianceReference{{Framework: "800-53", Control: "CM-7"}, {Framework: "CSCv7", Control: "5.1"}},
+	}}
+	if !reflect.DeepEqual(findings, want) {
+		t.Fatalf("got findings: %+v, expected: %+v", findings, want)
+	}
+}
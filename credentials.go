@@ -0,0 +1,312 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const redacted = "********"
+
+// secretFields are the setting names whose values are never printed or logged
+var secretFields = []string{"password", "passphrase", "private_key", "secret", "token", "community"}
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range secretFields {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CredentialSettings holds the settings of a credential, as described by the
+// configuration of its CredentialType. Secret values are redacted when the
+// settings are printed.
+type CredentialSettings map[string]interface{}
+
+// String returns the settings with their secret values redacted
+func (s CredentialSettings) String() string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := s[k]
+		if isSecretField(k) {
+			v = redacted
+		}
+		parts = append(parts, fmt.Sprintf("%s:%v", k, v))
+	}
+	return "map[" + strings.Join(parts, " ") + "]"
+}
+
+// GoString returns the settings with their secret values redacted
+func (s CredentialSettings) GoString() string {
+	return "restuss.CredentialSettings" + s.String()
+}
+
+// SSHCredential holds the settings of an SSH credential
+type SSHCredential struct {
+	// AuthMethod is one of "password", "public key", "certificate" or "Kerberos".
+	AuthMethod            string `json:"auth_method"`
+	Username              string `json:"username"`
+	Password              string `json:"password,omitempty"`
+	PrivateKey            string `json:"private_key,omitempty"`
+	PrivateKeyPassphrase  string `json:"private_key_passphrase,omitempty"`
+	ElevatePrivilegesWith string `json:"elevate_privileges_with,omitempty"`
+	EscalationAccount     string `json:"escalation_account,omitempty"`
+	EscalationPassword    string `json:"escalation_password,omitempty"`
+}
+
+// Settings returns the credential as CredentialSettings
+func (c SSHCredential) Settings() CredentialSettings {
+	return toSettings(c)
+}
+
+// String returns the credential with its secret values redacted
+func (c SSHCredential) String() string {
+	return c.Settings().String()
+}
+
+// GoString returns the credential with its secret values redacted
+func (c SSHCredential) GoString() string {
+	return "restuss.SSHCredential" + c.String()
+}
+
+// WindowsCredential holds the settings of a Windows credential
+type WindowsCredential struct {
+	// AuthMethod is one of "Password", "Kerberos", "LM Hash" or "NTLM Hash".
+	AuthMethod string `json:"auth_method"`
+	Username   string `json:"username"`
+	Password   string `json:"password,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+}
+
+// Settings returns the credential as CredentialSettings
+func (c WindowsCredential) Settings() CredentialSettings {
+	return toSettings(c)
+}
+
+// String returns the credential with its secret values redacted
+func (c WindowsCredential) String() string {
+	return c.Settings().String()
+}
+
+// GoString returns the credential with its secret values redacted
+func (c WindowsCredential) GoString() string {
+	return "restuss.WindowsCredential" + c.String()
+}
+
+func toSettings(v interface{}) CredentialSettings {
+	buf, _ := json.Marshal(v)
+	s := CredentialSettings{}
+	_ = json.Unmarshal(buf, &s)
+	return s
+}
+
+// AddManaged attaches the managed credential with the given UUID
+func (s *ScanCredentials) AddManaged(category, credentialType, uuid string) {
+	s.AddSettings(category, credentialType, CredentialSettings{"id": uuid})
+}
+
+// AddSettings attaches a credential defined by its settings, for instance
+// AddSettings("Host", "SSH", SSHCredential{...}.Settings())
+func (s *ScanCredentials) AddSettings(category, credentialType string, settings CredentialSettings) {
+	if s.Add == nil {
+		s.Add = map[string]map[string][]CredentialSettings{}
+	}
+	if s.Add[category] == nil {
+		s.Add[category] = map[string][]CredentialSettings{}
+	}
+	s.Add[category][credentialType] = append(s.Add[category][credentialType], settings)
+}
+
+// ListCredentialTypes returns the credential types available, grouped by category
+func (c *NessusClient) ListCredentialTypes() ([]CredentialCategory, error) {
+	return c.ListCredentialTypesContext(context.Background())
+}
+
+// ListCredentialTypesContext returns the credential types available using the given context.
+func (c *NessusClient) ListCredentialTypesContext(ctx context.Context) ([]CredentialCategory, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/credentials/types", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Categories []CredentialCategory `json:"credentials"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Categories, nil
+}
+
+// ListCredentials returns all the managed credentials, without their settings
+func (c *NessusClient) ListCredentials() ([]Credential, error) {
+	return c.ListCredentialsContext(context.Background())
+}
+
+// ListCredentialsContext returns all the managed credentials using the given context.
+func (c *NessusClient) ListCredentialsContext(ctx context.Context) ([]Credential, error) {
+	var credentials []Credential
+	err := c.getAllPages(ctx, "/credentials", nil, "credentials", func(raw json.RawMessage) (int, error) {
+		var page []Credential
+		err := json.Unmarshal(raw, &page)
+		credentials = append(credentials, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// GetCredential retrieves a managed credential by UUID
+func (c *NessusClient) GetCredential(uuid string) (*Credential, error) {
+	return c.GetCredentialContext(context.Background(), uuid)
+}
+
+// GetCredentialContext retrieves a managed credential by UUID using the given context.
+func (c *NessusClient) GetCredentialContext(ctx context.Context, uuid string) (*Credential, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/credentials/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &Credential{}
+	err = c.performCallAndReadResponse(req, cred)
+	if err != nil {
+		return nil, err
+	}
+	cred.UUID = uuid
+
+	return cred, nil
+}
+
+// CreateCredential creates a managed credential, returning its UUID
+func (c *NessusClient) CreateCredential(cred *Credential) (string, error) {
+	return c.CreateCredentialContext(context.Background(), cred)
+}
+
+// CreateCredentialContext creates a managed credential using the given context, returning its UUID
+func (c *NessusClient) CreateCredentialContext(ctx context.Context, cred *Credential) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/credentials", cred)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		UUID string `json:"uuid"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.UUID, nil
+}
+
+// EditCredential updates the managed credential with the UUID of the given credential
+func (c *NessusClient) EditCredential(cred *Credential) error {
+	return c.EditCredentialContext(context.Background(), cred)
+}
+
+// EditCredentialContext updates the managed credential with the UUID of the
+// given credential using the given context.
+func (c *NessusClient) EditCredentialContext(ctx context.Context, cred *Credential) error {
+	payload := map[string]interface{}{
+		"name":        cred.Name,
+		"description": cred.Description,
+		"ad_hoc":      cred.AdHoc,
+		"settings":    cred.Settings,
+	}
+	if cred.Permissions != nil {
+		payload["permissions"] = cred.Permissions
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, "/credentials/"+url.PathEscape(cred.UUID), payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// DeleteCredential removes the managed credential with the given UUID
+func (c *NessusClient) DeleteCredential(uuid string) error {
+	return c.DeleteCredentialContext(context.Background(), uuid)
+}
+
+// DeleteCredentialContext removes the managed credential with the given UUID using the given context.
+func (c *NessusClient) DeleteCredentialContext(ctx context.Context, uuid string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/credentials/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// EditPolicy updates the settings and credentials of the policy with the ID of the given policy
+func (c *NessusClient) EditPolicy(policy *Policy) error {
+	return c.EditPolicyContext(context.Background(), policy)
+}
+
+// EditPolicyContext updates the settings and credentials of the policy with
+// the ID of the given policy using the given context.
+func (c *NessusClient) EditPolicyContext(ctx context.Context, policy *Policy) error {
+	payload := map[string]interface{}{
+		"uuid":     policy.UUID,
+		"settings": policy.Settings,
+	}
+	if policy.Credentials != nil {
+		payload["credentials"] = policy.Credentials
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/policies/%d", policy.ID), payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// redactBody returns a JSON body with the values of secret fields replaced,
+// bodies which are not JSON are returned as they are.
+func redactBody(body []byte) string {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+	buf, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(buf)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if _, isString := val.(string); isString && isSecretField(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+	}
+	return v
+}
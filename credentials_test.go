@@ -0,0 +1,35 @@
+package restuss
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCredentialsRedaction(t *testing.T) {
+	ssh := SSHCredential{
+		AuthMethod:         "password",
+		Username:           "scanner",
+		Password:           "hunter2",
+		EscalationPassword: "hunter3",
+	}
+
+	tests := []struct {
+		name string
+		got  string
+	}{
+		{name: "settings", got: fmt.Sprintf("%v", ssh.Settings())},
+		{name: "typed", got: fmt.Sprintf("%v", ssh)},
+		{name: "go syntax", got: fmt.Sprintf("%#v", ssh)},
+		{name: "body", got: redactBody([]byte(`{"settings":{"password":"hunter2","escalation_password":"hunter3","username":"scanner"}}`))},
+	}
+
+	for _, tc := range tests {
+		if strings.Contains(tc.got, "hunter") {
+			t.Fatalf("%s: secret not redacted: %s", tc.name, tc.got)
+		}
+		if !strings.Contains(tc.got, "scanner") {
+			t.Fatalf("%s: non secret field missing: %s", tc.name, tc.got)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package restuss
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CycloneDXBOM represents a CycloneDX 1.5 vulnerability BOM
+type CycloneDXBOM struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber"`
+	Version         int                      `json:"version"`
+	Metadata        CycloneDXMetadata        `json:"metadata"`
+	Components      []CycloneDXComponent     `json:"components"`
+	Vulnerabilities []CycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+// CycloneDXMetadata represents the metadata of a CycloneDX BOM
+type CycloneDXMetadata struct {
+	Timestamp time.Time `json:"timestamp"`
+	Tools     struct {
+		Components []CycloneDXComponent `json:"components"`
+	} `json:"tools"`
+}
+
+// CycloneDXComponent represents a component, an asset in Nessus terms
+type CycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXProperty represents a name-value property of a component
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CycloneDXVulnerability represents a vulnerability, a plugin in Nessus terms,
+// and the components it affects
+type CycloneDXVulnerability struct {
+	BOMRef         string               `json:"bom-ref"`
+	ID             string               `json:"id"`
+	Source         CycloneDXSource      `json:"source"`
+	References     []CycloneDXReference `json:"references,omitempty"`
+	Ratings        []CycloneDXRating    `json:"ratings,omitempty"`
+	CWEs           []int                `json:"cwes,omitempty"`
+	Description    string               `json:"description,omitempty"`
+	Detail         string               `json:"detail,omitempty"`
+	Recommendation string               `json:"recommendation,omitempty"`
+	Advisories     []CycloneDXAdvisory  `json:"advisories,omitempty"`
+	Affects        []CycloneDXAffect    `json:"affects"`
+}
+
+// CycloneDXSource represents the source of a vulnerability or rating
+type CycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// CycloneDXReference represents another identifier of a vulnerability
+type CycloneDXReference struct {
+	ID     string          `json:"id"`
+	Source CycloneDXSource `json:"source"`
+}
+
+// CycloneDXRating represents a severity rating of a vulnerability
+type CycloneDXRating struct {
+	Source   *CycloneDXSource `json:"source,omitempty"`
+	Score    *float32         `json:"score,omitempty"`
+	Severity string           `json:"severity"`
+	Method   string           `json:"method,omitempty"`
+	Vector   string           `json:"vector,omitempty"`
+}
+
+// CycloneDXAdvisory represents an advisory about a vulnerability
+type CycloneDXAdvisory struct {
+	URL string `json:"url"`
+}
+
+// CycloneDXAffect represents a component affected by a vulnerability
+type CycloneDXAffect struct {
+	Ref string `json:"ref"`
+}
+
+// OpenVEXDocument represents an OpenVEX document
+type OpenVEXDocument struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Version    int                `json:"version"`
+	Statements []OpenVEXStatement `json:"statements"`
+}
+
+// OpenVEXStatement represents the status of a vulnerability on some products,
+// assets in Nessus terms
+type OpenVEXStatement struct {
+	Vulnerability   OpenVEXVulnerability `json:"vulnerability"`
+	Products        []OpenVEXProduct     `json:"products"`
+	Status          string               `json:"status"`
+	ActionStatement string               `json:"action_statement,omitempty"`
+}
+
+// OpenVEXVulnerability identifies the vulnerability of a statement
+type OpenVEXVulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// OpenVEXProduct identifies a product of a statement
+type OpenVEXProduct struct {
+	ID string `json:"@id"`
+}
+
+// OpenVEX statuses
+const (
+	VEXAffected           = "affected"
+	VEXFixed              = "fixed"
+	VEXUnderInvestigation = "under_investigation"
+)
+
+// VulnerabilityBOM accumulates findings and builds a CycloneDX vulnerability
+// BOM or an OpenVEX document out of them. Assets become components keyed by
+// their FQDN or IP, and plugins become vulnerabilities.
+type VulnerabilityBOM struct {
+	// Author is the author of the OpenVEX statements
+	Author string
+	// Timestamp is the time of the documents, the creation time by default
+	Timestamp time.Time
+
+	components []CycloneDXComponent
+	hosts      map[string]bool
+	vulns      []*bomVulnerability
+	index      map[int]*bomVulnerability
+	plugins    map[int]*Plugin
+}
+
+type bomVulnerability struct {
+	pluginID int
+	severity int
+	def      findingDefinition
+	hosts    []string
+	states   map[string]string
+}
+
+// findingDefinition is the plugin information of a finding
+type findingDefinition struct {
+	Name, Description, Synopsis, Solution string
+	CVSS3Score, CVSS2Score                *float32
+	CVSS3Vector, CVSS2Vector              string
+	CVE, CWE, SeeAlso                     []string
+}
+
+// NewVulnerabilityBOM returns an empty VulnerabilityBOM
+func NewVulnerabilityBOM() *VulnerabilityBOM {
+	return &VulnerabilityBOM{
+		Author:    "restuss",
+		Timestamp: time.Now().UTC(),
+		hosts:     map[string]bool{},
+		index:     map[int]*bomVulnerability{},
+		plugins:   map[int]*Plugin{},
+	}
+}
+
+// AddPlugin adds the details of a plugin, used to complete the information
+// the findings of the plugin lack
+func (b *VulnerabilityBOM) AddPlugin(plugin *Plugin) {
+	b.plugins[int(plugin.ID)] = plugin
+}
+
+// AddFindings adds findings, assetName is used as host when the findings
+// don't carry their asset attributes
+func (b *VulnerabilityBOM) AddFindings(assetName string, findings []Finding) {
+	for _, f := range findings {
+		host := findingHost(f)
+		if host == "" {
+			host = assetName
+		}
+		b.addComponent(host, f.Asset)
+
+		v, ok := b.index[f.Definition.ID]
+		if !ok {
+			d := f.Definition
+			v = &bomVulnerability{
+				pluginID: d.ID,
+				def: findingDefinition{
+					Name:        d.Name,
+					Description: d.Description,
+					Synopsis:    d.Synopsis,
+					Solution:    d.Solution,
+					CVSS3Score:  d.CVSS3.BaseScore,
+					CVSS2Score:  d.CVSS2.BaseScore,
+					CVSS3Vector: d.CVSS3.Vector,
+					CVSS2Vector: d.CVSS2.Vector,
+					CVE:         d.CVE,
+					CWE:         d.CWE,
+					SeeAlso:     d.SeeAlso,
+				},
+				states: map[string]string{},
+			}
+			b.index[d.ID] = v
+			b.vulns = append(b.vulns, v)
+		}
+		if f.Severity > v.severity {
+			v.severity = f.Severity
+		}
+		if _, ok := v.states[host]; !ok {
+			v.hosts = append(v.hosts, host)
+		}
+		v.states[host] = f.State
+	}
+}
+
+// BOM returns the CycloneDX BOM of the findings added so far
+func (b *VulnerabilityBOM) BOM() (*CycloneDXBOM, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	bom := &CycloneDXBOM{
+		BOMFormat:       "CycloneDX",
+		SpecVersion:     "1.5",
+		SerialNumber:    "urn:uuid:" + serial,
+		Version:         1,
+		Components:      make([]CycloneDXComponent, len(b.components)),
+		Vulnerabilities: make([]CycloneDXVulnerability, 0, len(b.vulns)),
+	}
+	bom.Metadata.Timestamp = b.Timestamp
+	bom.Metadata.Tools.Components = []CycloneDXComponent{{Type: "application", Name: "restuss"}}
+	copy(bom.Components, b.components)
+
+	for _, v := range b.vulns {
+		d := b.definition(v)
+		id := strconv.Itoa(v.pluginID)
+		cv := CycloneDXVulnerability{
+			BOMRef: "plugin:" + id,
+			ID:     id,
+			Source: CycloneDXSource{
+				Name: "Tenable",
+				URL:  "https://www.tenable.com/plugins/nessus/" + id,
+			},
+			Description:    d.Synopsis,
+			Detail:         d.Description,
+			Recommendation: d.Solution,
+			Ratings: []CycloneDXRating{{
+				Source:   &CycloneDXSource{Name: "Tenable"},
+				Severity: cycloneDXSeverity(v.severity),
+				Method:   "other",
+			}},
+		}
+		if cv.Description == "" {
+			cv.Description = d.Name
+		}
+		for _, cve := range d.CVE {
+			cv.References = append(cv.References, CycloneDXReference{
+				ID:     cve,
+				Source: CycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + cve},
+			})
+		}
+		if d.CVSS3Score != nil {
+			method := "CVSSv3"
+			if strings.HasPrefix(d.CVSS3Vector, "CVSS:3.1/") {
+				method = "CVSSv31"
+			}
+			cv.Ratings = append(cv.Ratings, CycloneDXRating{
+				Score:    d.CVSS3Score,
+				Severity: cvssSeverity(*d.CVSS3Score),
+				Method:   method,
+				Vector:   d.CVSS3Vector,
+			})
+		}
+		if d.CVSS2Score != nil {
+			cv.Ratings = append(cv.Ratings, CycloneDXRating{
+				Score:    d.CVSS2Score,
+				Severity: cvssSeverity(*d.CVSS2Score),
+				Method:   "CVSSv2",
+				Vector:   d.CVSS2Vector,
+			})
+		}
+		for _, cwe := range d.CWE {
+			n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(cwe), "CWE-"))
+			if err == nil {
+				cv.CWEs = append(cv.CWEs, n)
+			}
+		}
+		for _, u := range d.SeeAlso {
+			cv.Advisories = append(cv.Advisories, CycloneDXAdvisory{URL: u})
+		}
+		for _, h := range v.hosts {
+			cv.Affects = append(cv.Affects, CycloneDXAffect{Ref: componentRef(h)})
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, cv)
+	}
+	return bom, nil
+}
+
+// VEX returns the OpenVEX document of the findings added so far, with one
+// statement per plugin and finding state: open and reopened findings are
+// affected, fixed ones fixed and findings without state under investigation.
+func (b *VulnerabilityBOM) VEX() (*OpenVEXDocument, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	doc := &OpenVEXDocument{
+		Context:    "https://openvex.dev/ns/v0.2.0",
+		ID:         "urn:uuid:" + id,
+		Author:     b.Author,
+		Timestamp:  b.Timestamp,
+		Version:    1,
+		Statements: []OpenVEXStatement{},
+	}
+
+	for _, v := range b.vulns {
+		d := b.definition(v)
+		vuln := OpenVEXVulnerability{Name: "tenable-" + strconv.Itoa(v.pluginID)}
+		if len(d.CVE) > 0 {
+			vuln.Name = d.CVE[0]
+			vuln.Aliases = d.CVE[1:]
+		}
+
+		byStatus := map[string]*OpenVEXStatement{}
+		var statuses []string
+		for _, h := range v.hosts {
+			status := vexStatus(v.states[h])
+			s, ok := byStatus[status]
+			if !ok {
+				s = &OpenVEXStatement{Vulnerability: vuln, Status: status}
+				if status == VEXAffected {
+					s.ActionStatement = d.Solution
+					if s.ActionStatement == "" {
+						s.ActionStatement = "No remediation available"
+					}
+				}
+				byStatus[status] = s
+				statuses = append(statuses, status)
+			}
+			s.Products = append(s.Products, OpenVEXProduct{ID: h})
+		}
+		for _, status := range statuses {
+			doc.Statements = append(doc.Statements, *byStatus[status])
+		}
+	}
+	return doc, nil
+}
+
+// WriteCycloneDX writes the CycloneDX BOM as JSON to w
+func (b *VulnerabilityBOM) WriteCycloneDX(w io.Writer) error {
+	bom, err := b.BOM()
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(w, bom)
+}
+
+// WriteOpenVEX writes the OpenVEX document as JSON to w
+func (b *VulnerabilityBOM) WriteOpenVEX(w io.Writer) error {
+	doc, err := b.VEX()
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(w, doc)
+}
+
+func (b *VulnerabilityBOM) addComponent(host string, asset FindingAsset) {
+	if b.hosts[host] {
+		return
+	}
+	b.hosts[host] = true
+
+	c := CycloneDXComponent{Type: "device", BOMRef: componentRef(host), Name: host}
+	if asset.ID != "" {
+		c.Properties = append(c.Properties, CycloneDXProperty{Name: "tenable:asset:id", Value: asset.ID})
+	}
+	for _, ip := range asset.IPv4 {
+		c.Properties = append(c.Properties, CycloneDXProperty{Name: "tenable:asset:ipv4", Value: ip})
+	}
+	for _, ip := range asset.IPv6 {
+		c.Properties = append(c.Properties, CycloneDXProperty{Name: "tenable:asset:ipv6", Value: ip})
+	}
+	b.components = append(b.components, c)
+}
+
+// definition returns the plugin information of a vulnerability, completed
+// with the plugin details when they were added
+func (b *VulnerabilityBOM) definition(v *bomVulnerability) findingDefinition {
+	d := v.def
+	p, ok := b.plugins[v.pluginID]
+	if !ok {
+		return d
+	}
+
+	attrs := map[string][]string{}
+	for _, a := range p.Attributes {
+		attrs[a.Name] = append(attrs[a.Name], a.Value)
+	}
+	first := func(name string) string {
+		if len(attrs[name]) == 0 {
+			return ""
+		}
+		return attrs[name][0]
+	}
+	score := func(name string) *float32 {
+		f, err := strconv.ParseFloat(first(name), 32)
+		if err != nil {
+			return nil
+		}
+		s := float32(f)
+		return &s
+	}
+
+	if d.Name == "" {
+		d.Name = p.Name
+	}
+	if d.Description == "" {
+		d.Description = first("description")
+	}
+	if d.Synopsis == "" {
+		d.Synopsis = first("synopsis")
+	}
+	if d.Solution == "" {
+		d.Solution = first("solution")
+	}
+	if d.CVSS3Score == nil {
+		d.CVSS3Score = score("cvss3_base_score")
+	}
+	if d.CVSS2Score == nil {
+		d.CVSS2Score = score("cvss_base_score")
+	}
+	if d.CVSS3Vector == "" {
+		d.CVSS3Vector = first("cvss3_vector")
+	}
+	if d.CVSS2Vector == "" {
+		d.CVSS2Vector = first("cvss_vector")
+	}
+	if len(d.CVE) == 0 {
+		d.CVE = attrs["cve"]
+	}
+	if len(d.CWE) == 0 {
+		d.CWE = attrs["cwe"]
+	}
+	if len(d.SeeAlso) == 0 {
+		// see_also is returned as a single newline separated attribute
+		for _, s := range attrs["see_also"] {
+			d.SeeAlso = append(d.SeeAlso, strings.Fields(s)...)
+		}
+	}
+	return d
+}
+
+func componentRef(host string) string {
+	return "asset:" + host
+}
+
+func cycloneDXSeverity(severity int) string {
+	switch severity {
+	case 4:
+		return "critical"
+	case 3:
+		return "high"
+	case 2:
+		return "medium"
+	case 1:
+		return "low"
+	default:
+		return "info"
+	}
+}
+
+func cvssSeverity(score float32) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	default:
+		return "none"
+	}
+}
+
+func vexStatus(state string) string {
+	switch strings.ToLower(state) {
+	case FindingStateOpen, FindingStateReopened:
+		return VEXAffected
+	case FindingStateFixed:
+		return VEXFixed
+	default:
+		return VEXUnderInvestigation
+	}
+}
+
+func writeIndentedJSON(w io.Writer, v interface{}) error {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	var u [16]byte
+	_, err := rand.Read(u[:])
+	if err != nil {
+		return "", fmt.Errorf("Unable to generate UUID: %w", err)
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
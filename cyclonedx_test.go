@@ -0,0 +1,127 @@
+package restuss
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// checkGolden compares got with the content of the file testdata/name,
+// updating it instead with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		err := os.WriteFile(path, got, 0o644)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func testVulnerabilityBOM() *VulnerabilityBOM {
+	cvss3 := float32(9.8)
+	findings := make([]Finding, 4)
+	findings[0].State = "OPEN"
+	findings[0].Severity = 4
+	findings[0].Asset = FindingAsset{ID: "a-1", IPv4: StringList{"10.0.0.1"}, FQDN: StringList{"web.example.com"}}
+	findings[0].Definition.ID = 19506
+	findings[0].Definition.Name = "Critical thing"
+	findings[0].Definition.Synopsis = "The remote host is affected."
+	findings[0].Definition.Solution = "Upgrade."
+	findings[0].Definition.CVSS3.BaseScore = &cvss3
+	findings[0].Definition.CVSS3.Vector = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+	findings[0].Definition.CVE = []string{"CVE-2024-0001", "CVE-2024-0002"}
+	findings[0].Definition.CWE = []string{"CWE-79"}
+	findings[1] = findings[0]
+	findings[1].State = FindingStateFixed
+	findings[1].Severity = 3
+	findings[1].Asset = FindingAsset{ID: "a-2", IPv4: StringList{"10.0.0.2"}}
+	findings[2] = findings[0]
+	findings[2].State = ""
+	findings[2].Asset = FindingAsset{ID: "a-3", FQDN: StringList{"db.example.com"}}
+	findings[3].State = "Reopened"
+	findings[3].Severity = 1
+	findings[3].Asset = findings[0].Asset
+	findings[3].Definition.ID = 10107
+
+	b := NewVulnerabilityBOM()
+	b.Timestamp = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b.AddFindings("", findings)
+	b.AddPlugin(&Plugin{ID: 10107, Name: "HTTP Server Type and Version", Attributes: []PluginAttribute{
+		{Name: "synopsis", Value: "A web server is running on the remote host."},
+		{Name: "cvss_base_score", Value: "5.0"},
+		{Name: "see_also", Value: "https://example.com/a\nhttps://example.com/b"},
+	}})
+	return b
+}
+
+func TestVulnerabilityBOM(t *testing.T) {
+	uuid := regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	bom, err := testVulnerabilityBOM().BOM()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !uuid.MatchString(bom.SerialNumber) {
+		t.Fatalf("got serial number: %s, expected a random UUID", bom.SerialNumber)
+	}
+	bom.SerialNumber = "urn:uuid:00000000-0000-4000-8000-000000000000"
+	var buf bytes.Buffer
+	err = writeIndentedJSON(&buf, bom)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkGolden(t, "bom.cdx.json", buf.Bytes())
+
+	doc, err := testVulnerabilityBOM().VEX()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !uuid.MatchString(doc.ID) {
+		t.Fatalf("got ID: %s, expected a random UUID", doc.ID)
+	}
+	doc.ID = "urn:uuid:00000000-0000-4000-8000-000000000000"
+	buf.Reset()
+	err = writeIndentedJSON(&buf, doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkGolden(t, "vex.openvex.json", buf.Bytes())
+}
+
+func TestVEXStatus(t *testing.T) {
+	tests := []struct {
+		state string
+		want  string
+	}{
+		{FindingStateOpen, VEXAffected},
+		{"OPEN", VEXAffected},
+		{FindingStateReopened, VEXAffected},
+		{"REOPENED", VEXAffected},
+		{FindingStateFixed, VEXFixed},
+		{"Fixed", VEXFixed},
+		{"", VEXUnderInvestigation},
+		{"unknown", VEXUnderInvestigation},
+	}
+
+	for _, tc := range tests {
+		if got := vexStatus(tc.state); got != tc.want {
+			t.Fatalf("got status: %s for state %q, expected: %s", got, tc.state, tc.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package restuss
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HostFinding represents a plugin reported on a host and port
+type HostFinding struct {
+	Host       string
+	PluginID   int64
+	PluginName string
+	Port       int
+	Protocol   string
+	Service    string
+	Severity   int
+	// CVSS3 and CVSS2 are the base scores of the plugin, nil when unknown
+	CVSS3  *float32
+	CVSS2  *float32
+	Output string
+}
+
+// FindingKey identifies a finding across scans
+type FindingKey struct {
+	Host     string
+	PluginID int64
+	Port     int
+	Protocol string
+}
+
+// Key returns the key of the finding, hosts and protocols are compared
+// case insensitively
+func (f *HostFinding) Key() FindingKey {
+	return FindingKey{
+		Host:     strings.ToLower(f.Host),
+		PluginID: f.PluginID,
+		Port:     f.Port,
+		Protocol: strings.ToLower(f.Protocol),
+	}
+}
+
+// SeverityChange represents a finding found in both scans with a different
+// severity
+type SeverityChange struct {
+	Before HostFinding
+	After  HostFinding
+}
+
+// ScanDiff represents the differences between two scans
+type ScanDiff struct {
+	// New findings are only found in the later scan
+	New []HostFinding
+	// Resolved findings are only found in the earlier scan
+	Resolved []HostFinding
+	// Unchanged findings are found in both scans, with the same severity
+	Unchanged []HostFinding
+	// SeverityChanged findings are found in both scans, with a different
+	// severity
+	SeverityChanged []SeverityChange
+}
+
+// DiffHostFindings compares the findings of two scans, matching them by host,
+// plugin ID, port and protocol
+func DiffHostFindings(before, after []HostFinding) *ScanDiff {
+	beforeByKey := hostFindingsByKey(before)
+	afterByKey := hostFindingsByKey(after)
+
+	diff := &ScanDiff{}
+	for k, a := range afterByKey {
+		b, ok := beforeByKey[k]
+		switch {
+		case !ok:
+			diff.New = append(diff.New, a)
+		case a.Severity != b.Severity:
+			diff.SeverityChanged = append(diff.SeverityChanged, SeverityChange{Before: b, After: a})
+		default:
+			diff.Unchanged = append(diff.Unchanged, a)
+		}
+	}
+	for k, b := range beforeByKey {
+		if _, ok := afterByKey[k]; !ok {
+			diff.Resolved = append(diff.Resolved, b)
+		}
+	}
+
+	sortHostFindings(diff.New)
+	sortHostFindings(diff.Resolved)
+	sortHostFindings(diff.Unchanged)
+	sort.Slice(diff.SeverityChanged, func(i, j int) bool {
+		return lessFindingKey(diff.SeverityChanged[i].After.Key(), diff.SeverityChanged[j].After.Key())
+	})
+	return diff
+}
+
+// DiffNessusFiles compares two scans exported in the .nessus format
+func DiffNessusFiles(before, after io.Reader) (*ScanDiff, error) {
+	b, err := ParseNessus(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ParseNessus(after)
+	if err != nil {
+		return nil, err
+	}
+	return DiffHostFindings(b, a), nil
+}
+
+// DiffScans compares two runs of scans, identified by their ID and HistoryID.
+// The status of the exports is checked every pollInterval, every 5 seconds
+// when it is not positive.
+func (c *NessusClient) DiffScans(before, after *ScanDetail, pollInterval time.Duration) (*ScanDiff, error) {
+	return c.DiffScansContext(context.Background(), before, after, pollInterval)
+}
+
+// DiffScansContext compares two runs of scans using the given context. Scan
+// details only give counts per plugin, so both runs are exported in the
+// .nessus format to get the findings of each host and port.
+func (c *NessusClient) DiffScansContext(ctx context.Context, before, after *ScanDetail, pollInterval time.Duration) (*ScanDiff, error) {
+	b, err := c.GetHostFindingsContext(ctx, before.ID, before.HistoryID, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.GetHostFindingsContext(ctx, after.ID, after.HistoryID, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	return DiffHostFindings(b, a), nil
+}
+
+// DiffScanHistories compares two runs of a scan, checking the status of the
+// exports every pollInterval
+func (c *NessusClient) DiffScanHistories(scanID, beforeHistoryID, afterHistoryID int64, pollInterval time.Duration) (*ScanDiff, error) {
+	return c.DiffScanHistoriesContext(context.Background(), scanID, beforeHistoryID, afterHistoryID, pollInterval)
+}
+
+// DiffScanHistoriesContext compares two runs of a scan using the given context
+func (c *NessusClient) DiffScanHistoriesContext(ctx context.Context, scanID, beforeHistoryID, afterHistoryID int64, pollInterval time.Duration) (*ScanDiff, error) {
+	return c.DiffScansContext(ctx,
+		&ScanDetail{ID: scanID, HistoryID: beforeHistoryID},
+		&ScanDetail{ID: scanID, HistoryID: afterHistoryID},
+		pollInterval)
+}
+
+// GetHostFindings returns the findings of a run of a scan, zero historyID
+// meaning the last run. The status of the export is checked every
+// pollInterval, every 5 seconds when it is not positive.
+func (c *NessusClient) GetHostFindings(scanID, historyID int64, pollInterval time.Duration) ([]HostFinding, error) {
+	return c.GetHostFindingsContext(context.Background(), scanID, historyID, pollInterval)
+}
+
+// GetHostFindingsContext returns the findings of a run of a scan using the
+// given context. The run is exported in the .nessus format and parsed.
+func (c *NessusClient) GetHostFindingsContext(ctx context.Context, scanID, historyID int64, pollInterval time.Duration) ([]HostFinding, error) {
+	fileID, err := c.ExportScanContext(ctx, scanID, &ScanExport{Format: "nessus", HistoryID: historyID})
+	if err != nil {
+		return nil, err
+	}
+	err = c.WaitScanExportContext(ctx, scanID, fileID, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = c.DownloadScanExportContext(ctx, scanID, fileID, &buf)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNessus(&buf)
+}
+
+// HostFindingsFromFindings returns the findings of an asset as HostFinding,
+// asset is used as host when the findings don't carry their asset attributes
+func HostFindingsFromFindings(asset string, findings []Finding) []HostFinding {
+	result := make([]HostFinding, 0, len(findings))
+	for _, f := range findings {
+		host := findingHost(f)
+		if host == "" {
+			host = asset
+		}
+		result = append(result, HostFinding{
+			Host:       host,
+			PluginID:   int64(f.Definition.ID),
+			PluginName: f.Definition.Name,
+			Port:       f.Port,
+			Protocol:   f.Protocol,
+			Service:    f.Service,
+			Severity:   f.Severity,
+			CVSS3:      f.Definition.CVSS3.BaseScore,
+			CVSS2:      f.Definition.CVSS2.BaseScore,
+			Output:     f.Output,
+		})
+	}
+	return result
+}
+
+type nessusReportItem struct {
+	Port         int      `xml:"port,attr"`
+	Service      string   `xml:"svc_name,attr"`
+	Protocol     string   `xml:"protocol,attr"`
+	Severity     int      `xml:"severity,attr"`
+	PluginID     int64    `xml:"pluginID,attr"`
+	PluginName   string   `xml:"pluginName,attr"`
+	PluginOutput string   `xml:"plugin_output"`
+	CVSS3        *float32 `xml:"cvss3_base_score"`
+	CVSS2        *float32 `xml:"cvss_base_score"`
+}
+
+// ParseNessus parses a scan exported in the .nessus (v2) format, reading
+// one report item at a time
+func ParseNessus(r io.Reader) ([]HostFinding, error) {
+	var findings []HostFinding
+	var host string
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return findings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse .nessus file: %v", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "ReportHost":
+			host = ""
+			for _, a := range start.Attr {
+				if a.Name.Local == "name" {
+					host = a.Value
+				}
+			}
+		case "ReportItem":
+			var item nessusReportItem
+			err = dec.DecodeElement(&item, &start)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to parse .nessus file: %v", err)
+			}
+			findings = append(findings, HostFinding{
+				Host:       host,
+				PluginID:   item.PluginID,
+				PluginName: item.PluginName,
+				Port:       item.Port,
+				Protocol:   item.Protocol,
+				Service:    item.Service,
+				Severity:   item.Severity,
+				CVSS3:      item.CVSS3,
+				CVSS2:      item.CVSS2,
+				Output:     item.PluginOutput,
+			})
+		}
+	}
+}
+
+// hostFindingsByKey indexes findings by key, keeping the highest severity
+// when a key is reported more than once
+func hostFindingsByKey(findings []HostFinding) map[FindingKey]HostFinding {
+	byKey := make(map[FindingKey]HostFinding, len(findings))
+	for _, f := range findings {
+		k := f.Key()
+		if prev, ok := byKey[k]; ok && prev.Severity >= f.Severity {
+			continue
+		}
+		byKey[k] = f
+	}
+	return byKey
+}
+
+func sortHostFindings(findings []HostFinding) {
+	sort.Slice(findings, func(i, j int) bool {
+		return lessFindingKey(findings[i].Key(), findings[j].Key())
+	})
+}
+
+func lessFindingKey(a, b FindingKey) bool {
+	if a.Host != b.Host {
+		return a.Host < b.Host
+	}
+	if a.PluginID != b.PluginID {
+		return a.PluginID < b.PluginID
+	}
+	if a.Port != b.Port {
+		return a.Port < b.Port
+	}
+	return a.Protocol < b.Protocol
+}
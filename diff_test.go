@@ -0,0 +1,91 @@
+package restuss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const nessusBefore = `<?xml version="1.0" ?>
+<NessusClientData_v2>
+<Report name="staging">
+<ReportHost name="10.0.0.1">
+<HostProperties><tag name="host-ip">10.0.0.1</tag></HostProperties>
+<ReportItem port="443" svc_name="www" protocol="tcp" severity="2" pluginID="100" pluginName="Weak TLS">
+<plugin_output>TLSv1.0 enabled</plugin_output>
+</ReportItem>
+<ReportItem port="22" svc_name="ssh" protocol="tcp" severity="1" pluginID="200" pluginName="SSH banner"/>
+<ReportItem port="80" svc_name="www" protocol="tcp" severity="3" pluginID="300" pluginName="Old server"/>
+</ReportHost>
+</Report>
+</NessusClientData_v2>`
+
+const nessusAfter = `<?xml version="1.0" ?>
+<NessusClientData_v2>
+<Report name="staging">
+<ReportHost name="10.0.0.1">
+<ReportItem port="443" svc_name="www" protocol="TCP" severity="3" pluginID="100" pluginName="Weak TLS"/>
+<ReportItem port="22" svc_name="ssh" protocol="tcp" severity="1" pluginID="200" pluginName="SSH banner"/>
+</ReportHost>
+<ReportHost name="10.0.0.2">
+<ReportItem port="22" svc_name="ssh" protocol="tcp" severity="1" pluginID="200" pluginName="SSH banner"/>
+</ReportHost>
+</Report>
+</NessusClientData_v2>`
+
+func TestDiffNessusFiles(t *testing.T) {
+	diff, err := DiffNessusFiles(strings.NewReader(nessusBefore), strings.NewReader(nessusAfter))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(diff.New) != 1 || diff.New[0].Host != "10.0.0.2" {
+		t.Fatalf("got new: %+v, expected the finding of 10.0.0.2", diff.New)
+	}
+	if len(diff.Resolved) != 1 || diff.Resolved[0].PluginID != 300 {
+		t.Fatalf("got resolved: %+v, expected plugin 300", diff.Resolved)
+	}
+	if len(diff.Unchanged) != 1 || diff.Unchanged[0].PluginID != 200 {
+		t.Fatalf("got unchanged: %+v, expected plugin 200", diff.Unchanged)
+	}
+	if len(diff.SeverityChanged) != 1 {
+		t.Fatalf("got severity changes: %+v, expected 1", diff.SeverityChanged)
+	}
+	change := diff.SeverityChanged[0]
+	if change.Before.Severity != 2 || change.After.Severity != 3 || change.Before.Output != "TLSv1.0 enabled" {
+		t.Fatalf("got severity change: %+v", change)
+	}
+}
+
+func TestGetHostFindingsExportError(t *testing.T) {
+	statuses := []string{"loading", "error"}
+	var checks int
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/scans/1/export":
+				_, _ = w.Write([]byte(`{"file":7}`))
+			case "/scans/1/export/7/status":
+				status := statuses[checks]
+				if checks < len(statuses)-1 {
+					checks++
+				}
+				_, _ = w.Write([]byte(`{"status":"` + status + `"}`))
+			default:
+				t.Errorf("got unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	_, err = c.GetHostFindings(1, 0, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "error") {
+		t.Fatalf("got error: %v, expected the export failed", err)
+	}
+}
@@ -1,6 +1,11 @@
 package restuss
 
-import "time"
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
 
 // PersistedScan represents a Persisted Scan on Nessus API
 type PersistedScan struct {
@@ -11,6 +16,26 @@ type PersistedScan struct {
 	CreationDate         int64  `json:"creation_date"`
 	LastModificationDate int64  `json:"last_modification_date"`
 	Owner                string `json:"owner"`
+	FolderID             int64  `json:"folder_id"`
+}
+
+// Folder represents a scan folder returned by Nessus API
+type Folder struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DefaultTag  int64  `json:"default_tag"`
+	Custom      int64  `json:"custom"`
+	UnreadCount int64  `json:"unread_count"`
+}
+
+// ScanList represents the response of the scan listing on Nessus API.
+// Timestamp is the server time of the listing and can be used as
+// lastModificationDate on the next call.
+type ScanList struct {
+	Folders   []Folder         `json:"folders"`
+	Scans     []*PersistedScan `json:"scans"`
+	Timestamp int64            `json:"timestamp"`
 }
 
 // Vulnerability represents a Vulnerability returned by Nessus API
@@ -25,15 +50,37 @@ type Vulnerability struct {
 
 // ScanDetail represents Details from a Scan returned by Nessus API
 type ScanDetail struct {
-	ID              int64
+	ID int64
+	// HistoryID is the ID of the run of the scan, zero for the last one
+	HistoryID       int64           `json:"-"`
 	Info            Info            `json:"info"`
 	Hosts           []Host          `json:"hosts"`
 	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	// CompHosts and Compliance hold the results of compliance checks, see
+	// ComplianceFindings.
+	CompHosts  []Host          `json:"comphosts"`
+	Compliance []Vulnerability `json:"compliance"`
+	History    []ScanHistory   `json:"history"`
+}
+
+// ScanHistory represents a run of a scan
+type ScanHistory struct {
+	HistoryID            int64  `json:"history_id"`
+	UUID                 string `json:"uuid"`
+	Status               string `json:"status"`
+	CreationDate         int64  `json:"creation_date"`
+	LastModificationDate int64  `json:"last_modification_date"`
 }
 
 // Info represents detailed information from a Scan returned by Nessus API
 type Info struct {
-	Status string `json:"status"`
+	Status      string `json:"status"`
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	ScannerName string `json:"scanner_name"`
+	Targets     string `json:"targets"`
+	ScanStart   int64  `json:"scan_start"`
+	ScanEnd     int64  `json:"scan_end"`
 }
 
 // Host represents a host member of a scan
@@ -48,12 +95,26 @@ type ScanSettings struct {
 	Enabled  bool   `json:"enabled"`
 	Targets  string `json:"text_targets"`
 	PolicyID int64  `json:"policy_id"`
+	// ScannerID selects the scanner running the scan, the default scanner
+	// is used when empty.
+	ScannerID string `json:"scanner_id,omitempty"`
+	// AgentGroupIDs selects the agent groups scanned by agent templates.
+	AgentGroupIDs []string `json:"agent_group_id,omitempty"`
 }
 
 // Scan represents a Scan to be posted to Nessus API
 type Scan struct {
-	TemplateUUID string       `json:"uuid"`
-	Settings     ScanSettings `json:"settings"`
+	TemplateUUID string           `json:"uuid"`
+	Settings     ScanSettings     `json:"settings"`
+	Credentials  *ScanCredentials `json:"credentials,omitempty"`
+}
+
+// ScanExport represents a scan export request to be posted to Nessus API
+type ScanExport struct {
+	Format   string `json:"format"`
+	Chapters string `json:"chapters,omitempty"`
+	// HistoryID, when set, exports that run of the scan instead of the last one
+	HistoryID int64 `json:"-"`
 }
 
 // ScanTemplate represents a Template for a Scan returned by Nessus API
@@ -97,9 +158,10 @@ type PluginOutput struct {
 
 // Policy represents a policy returned by Nessus API
 type Policy struct {
-	ID       int64
-	UUID     string         `json:"uuid"`
-	Settings PolicySettings `json:"settings"`
+	ID          int64
+	UUID        string           `json:"uuid"`
+	Settings    PolicySettings   `json:"settings"`
+	Credentials *ScanCredentials `json:"credentials,omitempty"`
 }
 
 // PolicySettings represents a setting for policy returned by Nessus API
@@ -141,38 +203,721 @@ type Asset struct {
 	Updated       time.Time `json:"updated"`
 }
 
-// Finding represents the finding entity on Tenable.io.
+// Finding represents the finding entity on Tenable.io. Which attributes are
+// filled depends on the FindingFields requested, the attributes returned
+// but not modelled here are kept in Extra, those of the definition under
+// "definition.<name>".
 //
 // More info available at
 // https://developer.tenable.com/docs/tenable-plugin-attributes.
 type Finding struct {
-	Output     string `json:"output"`
-	ID         string `json:"id"`
-	Severity   int    `json:"severity"`
-	Port       int    `json:"port"`
-	Protocol   string `json:"protocol"`
-	Service    string `json:"service"`
-	Definition struct {
+	Output   string `json:"output"`
+	ID       string `json:"id"`
+	Severity int    `json:"severity"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	Service  string `json:"service"`
+	// State is one of FindingStateOpen, FindingStateReopened or
+	// FindingStateFixed. LastFixed is only set for fixed findings.
+	State        string       `json:"state"`
+	FirstFound   *time.Time   `json:"first_found"`
+	LastFound    *time.Time   `json:"last_found"`
+	LastFixed    *time.Time   `json:"last_fixed"`
+	RiskModified string       `json:"risk_modified"`
+	Asset        FindingAsset `json:"asset"`
+	Definition   struct {
 		ID          int    `json:"id"` // plugin_id
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Synopsis    string `json:"synopsis"`
-		Solution    string `json"solution"`
+		Solution    string `json:"solution"`
 		CVSS3       struct {
 			BaseScore *float32 `json:"base_score"`
+			Vector    string   `json:"vector"`
 		} `json:"cvss3"`
 		CVSS2 struct {
 			BaseScore *float32 `json:"base_score"`
+			Vector    string   `json:"vector"`
 		} `json:"cvss2"`
-		CWE     []string `json:"cwe"`
-		SeeAlso []string `json:"see_also"`
+		VPR struct {
+			Score *float32 `json:"score"`
+		} `json:"vpr"`
+		CVE              []string `json:"cve"`
+		ExploitAvailable *bool    `json:"exploit_available"`
+		CWE              []string `json:"cwe"`
+		SeeAlso          []string `json:"see_also"`
 	} `json:"definition"`
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the finding, keeping the attributes without a
+// matching field in Extra.
+func (f *Finding) UnmarshalJSON(data []byte) error {
+	type finding Finding
+	err := json.Unmarshal(data, (*finding)(f))
+	if err != nil {
+		return err
+	}
+
+	f.Extra = nil
+	return f.addExtra("", data, findingAttributes)
+}
+
+// addExtra adds the attributes of the JSON object data which aren't known to
+// Extra, their names prefixed with prefix
+func (f *Finding) addExtra(prefix string, data []byte, known map[string]bool) error {
+	var all map[string]json.RawMessage
+	err := json.Unmarshal(data, &all)
+	if err != nil {
+		return err
+	}
+	for k, v := range all {
+		if prefix == "" && k == "definition" && string(v) != "null" {
+			err = f.addExtra("definition.", v, definitionAttributes)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if known[k] {
+			continue
+		}
+		if f.Extra == nil {
+			f.Extra = map[string]json.RawMessage{}
+		}
+		f.Extra[prefix+k] = v
+	}
+	return nil
+}
+
+// findingAttributes and definitionAttributes are the attributes decoded into
+// Finding and Finding.Definition fields
+var (
+	findingAttributes    = jsonNames(reflect.TypeOf(Finding{}))
+	definitionAttributes = jsonNames(reflect.TypeOf(Finding{}.Definition))
+)
+
+func jsonNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			names[tag] = true
+		}
+	}
+	return names
+}
+
+// FindingAsset represents the asset attributes of a finding
+type FindingAsset struct {
+	ID   string     `json:"id"`
+	Name string     `json:"name"`
+	IPv4 StringList `json:"ipv4"`
+	IPv6 StringList `json:"ipv6"`
+	FQDN StringList `json:"fqdn"`
+}
+
+// StringList decodes both a JSON list of strings and a single string
+type StringList []string
+
+// UnmarshalJSON accepts a single string as a list of one element
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*l = StringList{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
 }
 
 // Pagination is used to iterate results for some endpoints. If the attribute
 // `Next` has content, needs to be passed as a parameter to the next request.
+// Offset based endpoints return `Offset` and `Total` instead.
 type Pagination struct {
-	Next  string `json:"next"`
-	Limit int    `json:"limit"`
-	Total int    `json:"total"`
+	Next   string `json:"next"`
+	Limit  int    `json:"limit"`
+	Total  int    `json:"total"`
+	Offset int    `json:"offset"`
+}
+
+// TagCategory represents a tag category on Tenable.io
+type TagCategory struct {
+	UUID        string    `json:"uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Reserved    bool      `json:"reserved"`
+	ValueCount  int       `json:"value_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UpdatedBy   string    `json:"updated_by"`
+}
+
+// TagValue represents a tag value on Tenable.io. Dynamic tags carry the
+// filter rules selecting the assets they are applied to.
+type TagValue struct {
+	UUID                string           `json:"uuid,omitempty"`
+	CategoryUUID        string           `json:"category_uuid,omitempty"`
+	CategoryName        string           `json:"category_name,omitempty"`
+	CategoryDescription string           `json:"category_description,omitempty"`
+	Value               string           `json:"value"`
+	Description         string           `json:"description,omitempty"`
+	Type                string           `json:"type,omitempty"`
+	Filters             *TagValueFilters `json:"filters,omitempty"`
+	CreatedAt           *time.Time       `json:"created_at,omitempty"`
+	CreatedBy           string           `json:"created_by,omitempty"`
+	UpdatedAt           *time.Time       `json:"updated_at,omitempty"`
+	UpdatedBy           string           `json:"updated_by,omitempty"`
+}
+
+// TagValueFilters represents the rules of a dynamic tag
+type TagValueFilters struct {
+	Asset TagFilterGroup `json:"asset"`
+}
+
+// UnmarshalJSON accepts the asset rules both as an object and as the JSON
+// encoded string returned when reading tag values.
+func (f *TagValueFilters) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Asset json.RawMessage `json:"asset"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	asset := []byte(raw.Asset)
+	var s string
+	if json.Unmarshal(asset, &s) == nil {
+		asset = []byte(s)
+	}
+	if len(asset) == 0 {
+		return nil
+	}
+	return json.Unmarshal(asset, &f.Asset)
+}
+
+// MarshalJSON encodes the asset rules as the JSON encoded string expected by
+// the API.
+func (f TagValueFilters) MarshalJSON() ([]byte, error) {
+	asset, err := json.Marshal(f.Asset)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]string{"asset": string(asset)})
+}
+
+// TagFilterGroup represents rules combined with and/or
+type TagFilterGroup struct {
+	And []TagFilterRule `json:"and,omitempty"`
+	Or  []TagFilterRule `json:"or,omitempty"`
+}
+
+// TagFilterRule represents a single dynamic tag rule, such as
+// {Field: "ipv4", Operator: "eq", Value: "10.0.0.1"}
+type TagFilterRule struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// TagAssignmentJob represents the status of a bulk tag assignment job
+type TagAssignmentJob struct {
+	UUID      string `json:"job_uuid"`
+	Status    string `json:"status"`
+	Processed int    `json:"processed"`
+	Total     int    `json:"total"`
+}
+
+// Scanner represents a scanner returned by Nessus API, including its health
+type Scanner struct {
+	ID                   int64  `json:"id"`
+	UUID                 string `json:"uuid"`
+	Name                 string `json:"name"`
+	Type                 string `json:"type"`
+	Status               string `json:"status"`
+	Linked               int    `json:"linked"`
+	Pool                 bool   `json:"pool"`
+	EngineVersion        string `json:"engine_version"`
+	Platform             string `json:"platform"`
+	LoadedPluginSet      string `json:"loaded_plugin_set"`
+	ScanCount            int    `json:"scan_count"`
+	LastConnect          int64  `json:"last_connect"`
+	CreationDate         int64  `json:"creation_date"`
+	LastModificationDate int64  `json:"last_modification_date"`
+	Owner                string `json:"owner"`
+	NetworkName          string `json:"network_name"`
+	// Load, when reported by the scanner.
+	NumScans       int     `json:"num_scans"`
+	NumHosts       int     `json:"num_hosts"`
+	NumSessions    int     `json:"num_sessions"`
+	NumTCPSessions int     `json:"num_tcp_sessions"`
+	LoadAvg        float64 `json:"loadavg"`
+}
+
+// ScannerScan represents a scan currently running on a scanner
+type ScannerScan struct {
+	ID                   string `json:"id"`
+	ScanID               int64  `json:"scan_id"`
+	Name                 string `json:"name"`
+	Status               string `json:"status"`
+	User                 string `json:"user"`
+	Remote               bool   `json:"remote"`
+	StartTime            int64  `json:"start_time"`
+	LastModificationDate int64  `json:"last_modification_date"`
+}
+
+// ScannerGroup represents a scanner group returned by Nessus API
+type ScannerGroup struct {
+	ID                   int64  `json:"id,omitempty"`
+	UUID                 string `json:"uuid,omitempty"`
+	Name                 string `json:"name"`
+	Type                 string `json:"type,omitempty"`
+	Owner                string `json:"owner,omitempty"`
+	ScannerCount         int    `json:"scanner_count,omitempty"`
+	CreationDate         int64  `json:"creation_date,omitempty"`
+	LastModificationDate int64  `json:"last_modification_date,omitempty"`
+}
+
+// Agent represents a linked agent returned by Nessus API
+type Agent struct {
+	ID           int64        `json:"id"`
+	UUID         string       `json:"uuid"`
+	Name         string       `json:"name"`
+	Platform     string       `json:"platform"`
+	Distro       string       `json:"distro"`
+	IP           string       `json:"ip"`
+	Status       string       `json:"status"`
+	CoreVersion  string       `json:"core_version"`
+	CoreBuild    string       `json:"core_build"`
+	PluginFeedID string       `json:"plugin_feed_id"`
+	LinkedOn     int64        `json:"linked_on"`
+	LastConnect  int64        `json:"last_connect"`
+	LastScanned  int64        `json:"last_scanned"`
+	NetworkUUID  string       `json:"network_uuid"`
+	NetworkName  string       `json:"network_name"`
+	Groups       []AgentGroup `json:"groups"`
+}
+
+// AgentGroup represents an agent group returned by Nessus API
+type AgentGroup struct {
+	ID                   int64  `json:"id"`
+	UUID                 string `json:"uuid"`
+	Name                 string `json:"name"`
+	Owner                string `json:"owner"`
+	AgentsCount          int    `json:"agents_count"`
+	CreationDate         int64  `json:"creation_date"`
+	LastModificationDate int64  `json:"last_modification_date"`
+}
+
+// AgentFilter represents a filter on agent listings, such as
+// {Field: "platform", Operator: "match", Value: "LINUX"}
+type AgentFilter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// AgentListOptions restricts the agents returned by a listing
+type AgentListOptions struct {
+	Filters []AgentFilter
+	// FilterType combines the filters with "and" (default) or "or".
+	FilterType string
+	// Wildcard matches the given text against all the wildcard fields.
+	Wildcard string
+}
+
+// AgentBulkJob represents the status of a bulk agent operation
+type AgentBulkJob struct {
+	TaskID         string `json:"task_id"`
+	ContainerUUID  string `json:"container_uuid"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+	TotalWorkUnits int    `json:"total_work_units"`
+	CompletedUnits int    `json:"total_work_units_completed"`
+	StartTime      int64  `json:"start_time"`
+	EndTime        int64  `json:"end_time"`
+	LastUpdateTime int64  `json:"last_update_time"`
+}
+
+// TargetGroup represents a target group returned by Nessus API. Members is a
+// comma separated list of hosts, ranges and CIDRs.
+type TargetGroup struct {
+	ID                   int64  `json:"id,omitempty"`
+	Name                 string `json:"name"`
+	Members              string `json:"members"`
+	Type                 string `json:"type,omitempty"`
+	Owner                string `json:"owner,omitempty"`
+	DefaultGroup         int    `json:"default_group,omitempty"`
+	LastModificationDate int64  `json:"last_modification_date,omitempty"`
+}
+
+// Exclusion represents hosts that must not be scanned, either always or
+// during the windows defined by its schedule. Members is a comma separated
+// list of hosts, ranges and CIDRs.
+type Exclusion struct {
+	ID                   int64              `json:"id,omitempty"`
+	Name                 string             `json:"name"`
+	Description          string             `json:"description,omitempty"`
+	Members              string             `json:"members"`
+	Schedule             *ExclusionSchedule `json:"schedule,omitempty"`
+	NetworkID            string             `json:"network_id,omitempty"`
+	CreationDate         int64              `json:"creation_date,omitempty"`
+	LastModificationDate int64              `json:"last_modification_date,omitempty"`
+}
+
+// ExclusionSchedule represents when an exclusion is active. StartTime and
+// EndTime use the "2006-01-02 15:04:05" layout in the given Timezone.
+type ExclusionSchedule struct {
+	Enabled   bool            `json:"enabled"`
+	StartTime string          `json:"starttime,omitempty"`
+	EndTime   string          `json:"endtime,omitempty"`
+	Timezone  string          `json:"timezone,omitempty"`
+	Rrules    *ExclusionRrule `json:"rrules,omitempty"`
+}
+
+// ExclusionRrule represents the recurrence of an exclusion schedule. Freq is
+// one of ONETIME, DAILY, WEEKLY, MONTHLY or YEARLY, ByWeekday a comma
+// separated list of SU, MO, TU, WE, TH, FR, SA and ByMonthDay a day of month.
+type ExclusionRrule struct {
+	Freq       string `json:"freq"`
+	Interval   int    `json:"interval,omitempty"`
+	ByWeekday  string `json:"byweekday,omitempty"`
+	ByMonthDay int    `json:"bymonthday,omitempty"`
+}
+
+// User permission levels
+const (
+	PermissionBasic         = 16
+	PermissionScanOperator  = 24
+	PermissionStandard      = 32
+	PermissionScanManager   = 40
+	PermissionAdministrator = 64
+)
+
+// User represents a user returned by Nessus API
+type User struct {
+	ID          int64  `json:"id,omitempty"`
+	UUID        string `json:"uuid,omitempty"`
+	Username    string `json:"username"`
+	Password    string `json:"password,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Permissions int    `json:"permissions"`
+	Type        string `json:"type,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	LastLogin   int64  `json:"lastlogin,omitempty"`
+}
+
+// APIKeys represents the API keys generated for a user, usable with NewKeyAuthProvider
+type APIKeys struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// Group represents a user group returned by Nessus API
+type Group struct {
+	ID          int64  `json:"id,omitempty"`
+	UUID        string `json:"uuid,omitempty"`
+	Name        string `json:"name"`
+	Permissions int    `json:"permissions,omitempty"`
+	UserCount   int    `json:"user_count,omitempty"`
+}
+
+// ACL represents a permission granted on an object to a user, a group or,
+// with type "default", to everybody.
+type ACL struct {
+	Type        string `json:"type"`
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	Owner       int    `json:"owner,omitempty"`
+	Permissions int    `json:"permissions"`
+}
+
+// Credential represents a managed credential on Tenable.io. Type is the
+// credential type, such as "SSH" or "Windows", and Category its category,
+// such as "Host".
+type Credential struct {
+	UUID        string                 `json:"uuid,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+	Type        string                 `json:"type"`
+	AdHoc       bool                   `json:"ad_hoc,omitempty"`
+	Settings    CredentialSettings     `json:"settings"`
+	Permissions []CredentialPermission `json:"permissions,omitempty"`
+}
+
+// UnmarshalJSON accepts category and type both as names and as the
+// {"id", "name"} objects returned when reading a credential.
+func (c *Credential) UnmarshalJSON(data []byte) error {
+	type credential Credential
+	var raw struct {
+		credential
+		Category json.RawMessage `json:"category"`
+		Type     json.RawMessage `json:"type"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	*c = Credential(raw.credential)
+	c.Category = nameOrObjectName(raw.Category)
+	c.Type = nameOrObjectName(raw.Type)
+	return nil
+}
+
+func nameOrObjectName(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(raw, &obj)
+	return obj.Name
+}
+
+// CredentialPermission represents the permission granted on a managed
+// credential to a user or a group: 32 to use it, 64 to edit it.
+type CredentialPermission struct {
+	GranteeUUID string `json:"grantee_uuid"`
+	Type        string `json:"type"`
+	Permissions int    `json:"permissions"`
+	Name        string `json:"name,omitempty"`
+}
+
+// CredentialCategory represents a category of credential types, such as "Host"
+type CredentialCategory struct {
+	ID    string           `json:"id"`
+	Name  string           `json:"category"`
+	Types []CredentialType `json:"types"`
+}
+
+// CredentialType represents a credential type and the fields of its settings
+type CredentialType struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Max           int               `json:"max"`
+	Configuration []CredentialField `json:"configuration"`
+}
+
+// CredentialField represents a field of the settings of a credential type
+type CredentialField struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Required bool        `json:"required"`
+	Hint     string      `json:"hint"`
+	Default  interface{} `json:"default"`
+}
+
+// ScanCredentials represents credentials attached to a scan or a policy,
+// indexed by category and type, for instance Add["Host"]["SSH"].
+type ScanCredentials struct {
+	Add map[string]map[string][]CredentialSettings `json:"add,omitempty"`
+}
+
+// Network represents a network on Tenable.io, used to tell apart assets with
+// overlapping addresses.
+type Network struct {
+	UUID          string `json:"uuid,omitempty"`
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	IsDefault     bool   `json:"is_default,omitempty"`
+	AssetsTTLDays int    `json:"assets_ttl_days,omitempty"`
+	ScannerCount  int    `json:"scanner_count,omitempty"`
+	OwnerUUID     string `json:"owner_uuid,omitempty"`
+	Created       int64  `json:"created,omitempty"`
+	CreatedBy     string `json:"created_by,omitempty"`
+	Modified      int64  `json:"modified,omitempty"`
+	ModifiedBy    string `json:"modified_by,omitempty"`
+	Deleted       int64  `json:"deleted,omitempty"`
+	DeletedBy     string `json:"deleted_by,omitempty"`
+}
+
+// AuditEvent represents an event of the Tenable.io audit log
+type AuditEvent struct {
+	ID          string            `json:"id"`
+	Action      string            `json:"action"`
+	CRUD        string            `json:"crud"`
+	IsFailure   bool              `json:"is_failure"`
+	IsAnonymous bool              `json:"is_anonymous"`
+	Received    time.Time         `json:"received"`
+	Description string            `json:"description"`
+	Actor       AuditActor        `json:"actor"`
+	Target      AuditTarget       `json:"target"`
+	Fields      []AuditEventField `json:"fields"`
+}
+
+// AuditActor represents the user performing an audited action
+type AuditActor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuditTarget represents the object of an audited action
+type AuditTarget struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// AuditEventField represents additional data attached to an audit event
+type AuditEventField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// WorkbenchVulnerability represents the vulnerability counts of a plugin
+// returned by the vulnerabilities workbench
+type WorkbenchVulnerability struct {
+	PluginID           int64           `json:"plugin_id"`
+	PluginName         string          `json:"plugin_name"`
+	PluginFamily       string          `json:"plugin_family"`
+	Count              int64           `json:"count"`
+	VulnerabilityState string          `json:"vulnerability_state"`
+	AcceptedCount      int64           `json:"accepted_count"`
+	RecastedCount      int64           `json:"recasted_count"`
+	Severity           int             `json:"severity"`
+	CountsBySeverity   []SeverityCount `json:"counts_by_severity"`
+	VPRScore           *float32        `json:"vpr_score"`
+	CVSSBaseScore      *float32        `json:"cvss_base_score"`
+	CVSS3BaseScore     *float32        `json:"cvss3_base_score"`
+}
+
+// SeverityCount represents a count of vulnerabilities of a severity
+type SeverityCount struct {
+	Count int64 `json:"count"`
+	Value int   `json:"value"`
+}
+
+// WorkbenchVulnerabilityInfo represents the details of a plugin returned by
+// the vulnerabilities workbench
+type WorkbenchVulnerabilityInfo struct {
+	Description string   `json:"description"`
+	Synopsis    string   `json:"synopsis"`
+	Solution    string   `json:"solution"`
+	SeeAlso     []string `json:"see_also"`
+	Count       int64    `json:"count"`
+	Severity    int      `json:"severity"`
+	Discovery   struct {
+		SeenFirst *time.Time `json:"seen_first"`
+		SeenLast  *time.Time `json:"seen_last"`
+	} `json:"discovery"`
+	PluginDetails struct {
+		Family           string     `json:"family"`
+		Name             string     `json:"name"`
+		Type             string     `json:"type"`
+		Version          string     `json:"version"`
+		Severity         int        `json:"severity"`
+		PublicationDate  *time.Time `json:"publication_date"`
+		ModificationDate *time.Time `json:"modification_date"`
+	} `json:"plugin_details"`
+	RiskInformation struct {
+		RiskFactor     string `json:"risk_factor"`
+		CVSSVector     string `json:"cvss_vector"`
+		CVSSBaseScore  string `json:"cvss_base_score"`
+		CVSS3Vector    string `json:"cvss3_vector"`
+		CVSS3BaseScore string `json:"cvss3_base_score"`
+	} `json:"risk_information"`
+	ReferenceInformation []struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	} `json:"reference_information"`
+	VulnerabilityInformation struct {
+		ExploitAvailable bool `json:"exploit_available"`
+	} `json:"vulnerability_information"`
+	VPR struct {
+		Score *float32 `json:"score"`
+	} `json:"vpr"`
+}
+
+// WorkbenchAsset represents an asset returned by the assets workbench
+type WorkbenchAsset struct {
+	ID              string   `json:"id"`
+	HasAgent        bool     `json:"has_agent"`
+	LastSeen        string   `json:"last_seen"`
+	IPv4            []string `json:"ipv4"`
+	IPv6            []string `json:"ipv6"`
+	FQDN            []string `json:"fqdn"`
+	NetbiosName     []string `json:"netbios_name"`
+	OperatingSystem []string `json:"operating_system"`
+	AgentName       []string `json:"agent_name"`
+	MACAddress      []string `json:"mac_address"`
+	Sources         []struct {
+		Name      string `json:"name"`
+		FirstSeen string `json:"first_seen"`
+		LastSeen  string `json:"last_seen"`
+	} `json:"sources"`
+	Severities []struct {
+		Count int64  `json:"count"`
+		Level int    `json:"level"`
+		Name  string `json:"name"`
+	} `json:"severities"`
+}
+
+// Compliance check results
+const (
+	CompliancePassed  = "PASSED"
+	ComplianceFailed  = "FAILED"
+	ComplianceWarning = "WARNING"
+	ComplianceError   = "ERROR"
+)
+
+// ComplianceFinding represents the result of a compliance (audit) check
+type ComplianceFinding struct {
+	AssetUUID string `json:"asset_uuid"`
+	// Hostname is the host of the results of a scan, which have no asset
+	// UUID.
+	Hostname      string                `json:"hostname,omitempty"`
+	FirstSeen     *time.Time            `json:"first_seen"`
+	LastSeen      *time.Time            `json:"last_seen"`
+	LastObserved  *time.Time            `json:"last_observed"`
+	State         string                `json:"state"`
+	Status        string                `json:"status"`
+	PluginID      int64                 `json:"plugin_id"`
+	CheckID       string                `json:"check_id"`
+	CheckName     string                `json:"check_name"`
+	CheckInfo     string                `json:"check_info"`
+	ActualValue   string                `json:"actual_value"`
+	ExpectedValue string                `json:"expected_value"`
+	AuditFile     string                `json:"audit_file"`
+	Solution      string                `json:"solution"`
+	SeeAlso       string                `json:"see_also"`
+	Reference     []ComplianceReference `json:"reference"`
+}
+
+// ComplianceReference represents a control of a framework, such as CIS, a
+// compliance check is related to
+type ComplianceReference struct {
+	Framework string `json:"framework"`
+	Control   string `json:"control"`
+}
+
+// ComplianceExportRequest represents a compliance export to be posted to Tenable.io API
+type ComplianceExportRequest struct {
+	NumFindings int                      `json:"num_findings,omitempty"`
+	Assets      []string                 `json:"asset,omitempty"`
+	Filters     *ComplianceExportFilters `json:"filters,omitempty"`
+}
+
+// ComplianceExportFilters restricts the findings of a compliance export,
+// times are Unix timestamps.
+type ComplianceExportFilters struct {
+	FirstSeen         int64    `json:"first_seen,omitempty"`
+	LastSeen          int64    `json:"last_seen,omitempty"`
+	LastObserved      int64    `json:"last_observed,omitempty"`
+	State             []string `json:"state,omitempty"`
+	ComplianceResults []string `json:"compliance_results,omitempty"`
+}
+
+// ComplianceExportStatus represents the status of a compliance export job
+type ComplianceExportStatus struct {
+	Status          string `json:"status"`
+	ChunksAvailable []int  `json:"chunks_available"`
+	ChunksFailed    []int  `json:"chunks_failed"`
+	ChunksCancelled []int  `json:"chunks_cancelled"`
+	TotalChunks     int    `json:"total_chunks"`
 }
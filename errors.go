@@ -0,0 +1,57 @@
+package restuss
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RetryLimitError is returned when a call kept receiving non-successful
+// status codes until the retry limit was exceeded. StatusCode and Body are
+// those of the last attempt, the values of the secret fields of the body being
+// redacted.
+type RetryLimitError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *RetryLimitError) Error() string {
+	return "Retry limit exceeded"
+}
+
+// ScanFailedError is returned when a scan waited for finished without
+// completing, Status being "canceled" or "aborted".
+type ScanFailedError struct {
+	ScanID int64
+	Status string
+}
+
+func (e *ScanFailedError) Error() string {
+	return fmt.Sprintf("Scan %d finished with status: %s", e.ScanID, e.Status)
+}
+
+// IsTemporary reports whether the error was caused by rate limiting or a
+// server side failure, and so the call may succeed if tried again later.
+func IsTemporary(err error) bool {
+	var rle *RetryLimitError
+	if !errors.As(err, &rle) {
+		return false
+	}
+	return rle.StatusCode == http.StatusTooManyRequests || rle.StatusCode >= 500
+}
+
+// IsNotFound reports whether the error was caused by a missing resource
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether the error was caused by missing or
+// insufficient credentials
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized) || hasStatusCode(err, http.StatusForbidden)
+}
+
+func hasStatusCode(err error, code int) bool {
+	var rle *RetryLimitError
+	return errors.As(err, &rle) && rle.StatusCode == code
+}
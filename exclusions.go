@@ -0,0 +1,362 @@
+package restuss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const exclusionTimeLayout = "2006-01-02 15:04:05"
+
+// ListExclusions returns all the exclusions
+func (c *NessusClient) ListExclusions() ([]Exclusion, error) {
+	return c.ListExclusionsContext(context.Background())
+}
+
+// ListExclusionsContext returns all the exclusions using the given context.
+func (c *NessusClient) ListExclusionsContext(ctx context.Context) ([]Exclusion, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/exclusions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Exclusions []Exclusion `json:"exclusions"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Exclusions, nil
+}
+
+// GetExclusion retrieves an exclusion by ID
+func (c *NessusClient) GetExclusion(ID int64) (*Exclusion, error) {
+	return c.GetExclusionContext(context.Background(), ID)
+}
+
+// GetExclusionContext retrieves an exclusion by ID using the given context.
+func (c *NessusClient) GetExclusionContext(ctx context.Context, ID int64) (*Exclusion, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/exclusions/%d", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Exclusion{}
+	err = c.performCallAndReadResponse(req, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// CreateExclusion creates an exclusion
+func (c *NessusClient) CreateExclusion(exclusion *Exclusion) (*Exclusion, error) {
+	return c.CreateExclusionContext(context.Background(), exclusion)
+}
+
+// CreateExclusionContext creates an exclusion using the given context.
+func (c *NessusClient) CreateExclusionContext(ctx context.Context, exclusion *Exclusion) (*Exclusion, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/exclusions", exclusion)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &Exclusion{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// EditExclusion updates the exclusion with the ID of the given exclusion
+func (c *NessusClient) EditExclusion(exclusion *Exclusion) (*Exclusion, error) {
+	return c.EditExclusionContext(context.Background(), exclusion)
+}
+
+// EditExclusionContext updates the exclusion with the ID of the given exclusion using the given context.
+func (c *NessusClient) EditExclusionContext(ctx context.Context, exclusion *Exclusion) (*Exclusion, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/exclusions/%d", exclusion.ID), exclusion)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := &Exclusion{}
+	err = c.performCallAndReadResponse(req, edited)
+	if err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+// DeleteExclusion removes the exclusion with the given ID
+func (c *NessusClient) DeleteExclusion(ID int64) error {
+	return c.DeleteExclusionContext(context.Background(), ID)
+}
+
+// DeleteExclusionContext removes the exclusion with the given ID using the given context.
+func (c *NessusClient) DeleteExclusionContext(ctx context.Context, ID int64) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/exclusions/%d", ID), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ExclusionConflict represents a scan target matching an active exclusion
+type ExclusionConflict struct {
+	Target    string
+	Exclusion Exclusion
+}
+
+// CheckScanExclusions returns the targets of the scan matching exclusions
+// active right now on the network with the given ID, the network of the
+// scanner running the scan. It is meant to be called before CreateScan.
+func (c *NessusClient) CheckScanExclusions(scan *Scan, networkID string) ([]ExclusionConflict, error) {
+	return c.CheckScanExclusionsContext(context.Background(), scan, networkID)
+}
+
+// CheckScanExclusionsContext returns the targets of the scan matching
+// exclusions active right now on the network with the given ID using the
+// given context.
+func (c *NessusClient) CheckScanExclusionsContext(ctx context.Context, scan *Scan, networkID string) ([]ExclusionConflict, error) {
+	exclusions, err := c.ListExclusionsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return CheckTargets(scan.Settings.Targets, networkID, exclusions, time.Now())
+}
+
+// CheckTargets returns the targets, in text_targets format, matching the
+// exclusions of the network with the given ID active at the given time. An
+// empty network ID, of the exclusions or the targets, is the default
+// network, so all the exclusions are considered on Nessus, which has no
+// networks.
+func CheckTargets(targets, networkID string, exclusions []Exclusion, at time.Time) ([]ExclusionConflict, error) {
+	scanTargets, err := parseTargets(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []ExclusionConflict
+	for _, e := range exclusions {
+		if !sameNetwork(e.NetworkID, networkID) {
+			continue
+		}
+		active, err := e.ActiveAt(at)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid schedule for exclusion %q: %v", e.Name, err)
+		}
+		if !active {
+			continue
+		}
+		members, err := parseTargets(e.Members)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid members for exclusion %q: %v", e.Name, err)
+		}
+		for _, t := range scanTargets {
+			for _, m := range members {
+				if t.overlaps(m) {
+					conflicts = append(conflicts, ExclusionConflict{Target: t.text, Exclusion: e})
+					break
+				}
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// sameNetwork reports whether the network IDs given are the same, an empty
+// one being the default network.
+func sameNetwork(a, b string) bool {
+	if a == "" {
+		a = DefaultNetworkID
+	}
+	if b == "" {
+		b = DefaultNetworkID
+	}
+	return strings.EqualFold(a, b)
+}
+
+// ActiveAt reports whether the exclusion applies at the given time.
+// Exclusions without an enabled schedule always apply.
+func (e *Exclusion) ActiveAt(at time.Time) (bool, error) {
+	s := e.Schedule
+	if s == nil || !s.Enabled {
+		return true, nil
+	}
+
+	loc := time.UTC
+	if s.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(s.Timezone)
+		if err != nil {
+			return false, err
+		}
+	}
+	start, err := time.ParseInLocation(exclusionTimeLayout, s.StartTime, loc)
+	if err != nil {
+		return false, err
+	}
+	end, err := time.ParseInLocation(exclusionTimeLayout, s.EndTime, loc)
+	if err != nil {
+		return false, err
+	}
+	at = at.In(loc)
+
+	freq := "ONETIME"
+	interval := 1
+	if s.Rrules != nil {
+		if s.Rrules.Freq != "" {
+			freq = strings.ToUpper(s.Rrules.Freq)
+		}
+		if s.Rrules.Interval > 0 {
+			interval = s.Rrules.Interval
+		}
+	}
+	if freq == "ONETIME" {
+		return !at.Before(start) && at.Before(end), nil
+	}
+
+	// Recurring windows start at the time of day of StartTime and last as
+	// long as the first window. Look back enough days to catch a window
+	// started before the given day and still open.
+	d := end.Sub(start)
+	if d <= 0 {
+		return false, errors.New("End time must be after start time")
+	}
+	days := int(d/(24*time.Hour)) + 1
+	for i := 0; i <= days; i++ {
+		day := at.AddDate(0, 0, -i)
+		occ := time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), start.Second(), 0, loc)
+		if occ.Before(start) || !recurs(freq, interval, s.Rrules, start, occ) {
+			continue
+		}
+		if !at.Before(occ) && at.Before(occ.Add(d)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// recurs reports whether a window starts on the day of occ
+func recurs(freq string, interval int, r *ExclusionRrule, start, occ time.Time) bool {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	occDay := time.Date(occ.Year(), occ.Month(), occ.Day(), 0, 0, 0, 0, occ.Location())
+	elapsedDays := int(occDay.Sub(startDay).Hours()/24 + 0.5)
+
+	switch freq {
+	case "DAILY":
+		return elapsedDays%interval == 0
+	case "WEEKLY":
+		startWeek := startDay.AddDate(0, 0, -int(startDay.Weekday()))
+		weeks := int(occDay.Sub(startWeek).Hours()/24+0.5) / 7
+		if weeks%interval != 0 {
+			return false
+		}
+		if r == nil || r.ByWeekday == "" {
+			return occ.Weekday() == start.Weekday()
+		}
+		for _, d := range strings.Split(r.ByWeekday, ",") {
+			if wd, ok := weekdays[strings.ToUpper(strings.TrimSpace(d))]; ok && wd == occ.Weekday() {
+				return true
+			}
+		}
+		return false
+	case "MONTHLY":
+		months := (occ.Year()-start.Year())*12 + int(occ.Month()) - int(start.Month())
+		if months%interval != 0 {
+			return false
+		}
+		day := start.Day()
+		if r != nil && r.ByMonthDay > 0 {
+			day = r.ByMonthDay
+		}
+		return occ.Day() == day
+	case "YEARLY":
+		years := occ.Year() - start.Year()
+		return years%interval == 0 && occ.Month() == start.Month() && occ.Day() == start.Day()
+	}
+
+	return false
+}
+
+// target represents a single entry of a comma separated target list, either
+// an inclusive IP range or a host name.
+type target struct {
+	text     string
+	host     string
+	from, to net.IP
+}
+
+func (t target) overlaps(o target) bool {
+	if t.host != "" || o.host != "" {
+		return t.host != "" && strings.EqualFold(t.host, o.host)
+	}
+	return bytes.Compare(t.from, o.to) <= 0 && bytes.Compare(o.from, t.to) <= 0
+}
+
+// parseTargets parses hosts, IPs, CIDRs and ranges such as 10.0.0.1-10.0.0.9
+// or 10.0.0.1-9, separated by commas or whitespace.
+func parseTargets(s string) ([]target, error) {
+	var targets []target
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t' || r == '\r'
+	})
+	for _, f := range fields {
+		t := target{text: f}
+		switch {
+		case strings.Contains(f, "/"):
+			_, n, err := net.ParseCIDR(f)
+			if err != nil {
+				return nil, err
+			}
+			t.from = n.IP.To16()
+			t.to = make(net.IP, len(t.from))
+			mask := n.Mask
+			if len(mask) == net.IPv4len {
+				mask = append(net.IPMask(net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff::")[:12]), mask...)
+			}
+			for i := range t.from {
+				t.to[i] = t.from[i] | ^mask[i]
+			}
+		case strings.Contains(f, "-") && net.ParseIP(f[:strings.Index(f, "-")]) != nil:
+			parts := strings.SplitN(f, "-", 2)
+			t.from = net.ParseIP(parts[0]).To16()
+			t.to = net.ParseIP(parts[1]).To16()
+			if t.to == nil && t.from.To4() != nil {
+				// Short form, only the last octet is given.
+				t.to = net.ParseIP(parts[0][:strings.LastIndex(parts[0], ".")+1] + parts[1]).To16()
+			}
+			if t.to == nil {
+				return nil, fmt.Errorf("Invalid range: %s", f)
+			}
+		default:
+			if ip := net.ParseIP(f); ip != nil {
+				t.from, t.to = ip.To16(), ip.To16()
+			} else {
+				t.host = f
+			}
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
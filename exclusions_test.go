@@ -0,0 +1,151 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExclusionRequests(t *testing.T) {
+	var (
+		requests []string
+		bodies   []Exclusion
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if r.Method == http.MethodPost || r.Method == http.MethodPut {
+				var e Exclusion
+				_ = json.NewDecoder(r.Body).Decode(&e)
+				bodies = append(bodies, e)
+				e.ID = 4
+				_ = json.NewEncoder(w).Encode(e)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	exclusion := &Exclusion{
+		Name:      "maintenance",
+		Members:   "10.0.0.0/24",
+		NetworkID: DefaultNetworkID,
+		Schedule:  &ExclusionSchedule{Enabled: true, StartTime: "2024-01-01 22:00:00", EndTime: "2024-01-02 02:00:00"},
+	}
+	created, err := c.CreateExclusion(exclusion)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.ID != 4 || created.Name != "maintenance" {
+		t.Fatalf("got created exclusion: %+v", *created)
+	}
+	created.Members = "10.0.1.0/24"
+	edited, err := c.EditExclusion(created)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if edited.Members != "10.0.1.0/24" {
+		t.Fatalf("got members: %s, expected: 10.0.1.0/24", edited.Members)
+	}
+	err = c.DeleteExclusion(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"POST /exclusions", "PUT /exclusions/4", "DELETE /exclusions/4"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("got requests: %v, expected: %v", requests, want)
+	}
+	if len(bodies) != 2 || !reflect.DeepEqual(bodies[0], *exclusion) || bodies[1].ID != 4 || bodies[1].Members != "10.0.1.0/24" {
+		t.Fatalf("got bodies: %+v, expected the exclusion created then edited", bodies)
+	}
+}
+
+func TestCheckTargets(t *testing.T) {
+	exclusions := []Exclusion{
+		{Name: "always", Members: "10.0.0.0/24, db.example.com"},
+		{
+			Name:    "weekly maintenance",
+			Members: "192.168.1.10-20",
+			Schedule: &ExclusionSchedule{
+				Enabled:   true,
+				StartTime: "2024-01-01 22:00:00",
+				EndTime:   "2024-01-02 02:00:00",
+				Timezone:  "UTC",
+				Rrules:    &ExclusionRrule{Freq: "WEEKLY", Interval: 1, ByWeekday: "MO,WE"},
+			},
+		},
+		{Name: "other network", Members: "172.16.0.1", NetworkID: "b2c3d4e5-0000-0000-0000-000000000001"},
+		{Name: "default network", Members: "172.16.0.2", NetworkID: DefaultNetworkID},
+	}
+
+	tests := []struct {
+		name    string
+		targets string
+		network string
+		at      time.Time
+		want    []string
+	}{
+		{
+			name:    "no conflict",
+			targets: "10.0.1.1,web.example.com",
+			at:      time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC),
+			want:    nil,
+		},
+		{
+			name:    "always active exclusion",
+			targets: "10.0.0.128/25,DB.example.com",
+			at:      time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC),
+			want:    []string{"10.0.0.128/25", "DB.example.com"},
+		},
+		{
+			name:    "inside maintenance window",
+			targets: "192.168.1.1-192.168.1.15",
+			at:      time.Date(2024, 1, 4, 1, 0, 0, 0, time.UTC), // Wednesday window.
+			want:    []string{"192.168.1.1-192.168.1.15"},
+		},
+		{
+			name:    "outside maintenance window",
+			targets: "192.168.1.1-192.168.1.15",
+			at:      time.Date(2024, 1, 5, 1, 0, 0, 0, time.UTC),
+			want:    nil,
+		},
+		{
+			name:    "exclusions of the default network",
+			targets: "172.16.0.1,172.16.0.2",
+			at:      time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC),
+			want:    []string{"172.16.0.2"},
+		},
+		{
+			name:    "exclusions of another network",
+			targets: "10.0.0.1,172.16.0.1,172.16.0.2",
+			network: "B2C3D4E5-0000-0000-0000-000000000001",
+			at:      time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC),
+			want:    []string{"172.16.0.1"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conflicts, err := CheckTargets(tc.targets, tc.network, exclusions, tc.at)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(conflicts) != len(tc.want) {
+				t.Fatalf("got: %v, expected: %v", conflicts, tc.want)
+			}
+			for i, c := range conflicts {
+				if c.Target != tc.want[i] {
+					t.Fatalf("got: %v, expected: %v", c.Target, tc.want[i])
+				}
+			}
+		})
+	}
+}
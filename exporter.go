@@ -0,0 +1,280 @@
+package restuss
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ExporterOptions configures an Exporter
+type ExporterOptions struct {
+	// Interval is the time between collections, one minute by default
+	Interval time.Duration
+	// ScanIDs restricts the scans whose vulnerabilities are collected, all
+	// the scans outside the trash when empty
+	ScanIDs []int64
+	// Assets lists the assets whose open findings are collected
+	Assets []string
+	// NetworkID restricts the findings of Assets to a network
+	NetworkID string
+	// Scanners enables the collection of the health of the scanners
+	Scanners bool
+}
+
+// Exporter periodically collects metrics about scans, vulnerabilities and
+// scanners and serves them, together with the metrics of the client, in the
+// Prometheus text format:
+//
+//	e := restuss.NewExporter(c, restuss.ExporterOptions{Scanners: true})
+//	go e.Run(ctx)
+//	http.Handle("/metrics", e)
+type Exporter struct {
+	client  *NessusClient
+	metrics *ClientMetrics
+	opts    ExporterOptions
+
+	mu       sync.Mutex
+	snapshot *exporterSnapshot
+}
+
+type exporterSnapshot struct {
+	time          time.Time
+	duration      time.Duration
+	success       bool
+	scansByStatus map[string]int
+	scans         []scanVulnerabilityCounts
+	assets        map[string][5]int
+	lastCompleted int64
+	scanners      []Scanner
+}
+
+type scanVulnerabilityCounts struct {
+	scan   *PersistedScan
+	counts [5]int64
+}
+
+// NewExporter returns an Exporter collecting through the given client. The
+// client counts its requests in a new ClientMetrics if it doesn't already,
+// so, as with SetMetrics, the exporter must be created before the client is
+// used by other goroutines.
+func NewExporter(client *NessusClient, opts ExporterOptions) *Exporter {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if client.metrics == nil {
+		client.SetMetrics(NewClientMetrics())
+	}
+	return &Exporter{client: client, metrics: client.metrics, opts: opts}
+}
+
+// Run collects the metrics straight away and then every Interval until the
+// context is done. Failed collections are logged and keep the metrics of
+// the last successful one.
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		err := e.Collect(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Error when collecting metrics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.opts.Interval):
+		}
+	}
+}
+
+// Collect collects the metrics once
+func (e *Exporter) Collect(ctx context.Context) error {
+	start := time.Now()
+	s, err := e.collect(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		if e.snapshot != nil {
+			e.snapshot.success = false
+		}
+		return err
+	}
+	s.time = start
+	s.duration = time.Since(start)
+	s.success = true
+	e.snapshot = s
+	return nil
+}
+
+func (e *Exporter) collect(ctx context.Context) (*exporterSnapshot, error) {
+	s := &exporterSnapshot{scansByStatus: map[string]int{}, assets: map[string][5]int{}}
+
+	list, err := e.client.GetScanListContext(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	trash := map[int64]bool{}
+	for _, f := range list.Folders {
+		if f.Type == "trash" {
+			trash[f.ID] = true
+		}
+	}
+	selected := map[int64]bool{}
+	for _, id := range e.opts.ScanIDs {
+		selected[id] = true
+	}
+
+	for _, scan := range list.Scans {
+		if trash[scan.FolderID] {
+			continue
+		}
+		s.scansByStatus[scan.Status]++
+		if scan.Status == "completed" && scan.LastModificationDate > s.lastCompleted {
+			s.lastCompleted = scan.LastModificationDate
+		}
+		if len(selected) > 0 && !selected[scan.ID] {
+			continue
+		}
+
+		detail, err := e.client.GetScanByIDContext(ctx, scan.ID)
+		if err != nil {
+			return nil, err
+		}
+		counts := scanVulnerabilityCounts{scan: scan}
+		for _, v := range detail.Vulnerabilities {
+			if v.Severity >= 0 && v.Severity < int64(len(counts.counts)) {
+				counts.counts[v.Severity] += v.Count
+			}
+		}
+		s.scans = append(s.scans, counts)
+	}
+
+	for _, asset := range e.opts.Assets {
+		findings, err := e.client.SearchFindingsByAssetName(ctx, asset, &FindingsOptions{
+			NetworkID: e.opts.NetworkID,
+			States:    []string{FindingStateOpen, FindingStateReopened},
+			Fields:    FindingFieldsMinimal,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var counts [5]int
+		for _, f := range findings {
+			if f.Severity >= 0 && f.Severity < len(counts) {
+				counts[f.Severity]++
+			}
+		}
+		s.assets[asset] = counts
+	}
+
+	if e.opts.Scanners {
+		s.scanners, err = e.client.ListScannersContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	e.write(&buf, time.Now())
+	_, err := e.metrics.WriteTo(&buf)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, err = w.Write(buf.Bytes())
+	if err != nil {
+		log.Printf("Error when writing metrics: %v", err)
+	}
+}
+
+func (e *Exporter) write(buf *bytes.Buffer, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	mw := &metricWriter{w: buf}
+	s := e.snapshot
+	mw.family("restuss_exporter_last_collect_success", "gauge", "Whether the last collection succeeded.")
+	if s == nil {
+		mw.sample("restuss_exporter_last_collect_success", 0)
+		return
+	}
+	mw.sample("restuss_exporter_last_collect_success", boolFloat(s.success))
+	mw.family("restuss_exporter_collect_duration_seconds", "gauge", "Duration of the last successful collection.")
+	mw.sample("restuss_exporter_collect_duration_seconds", s.duration.Seconds())
+	mw.family("restuss_exporter_last_collect_timestamp_seconds", "gauge", "Time of the last successful collection.")
+	mw.sample("restuss_exporter_last_collect_timestamp_seconds", float64(s.time.Unix()))
+
+	mw.family("restuss_scans", "gauge", "Scans outside the trash, by status.")
+	statuses := make([]string, 0, len(s.scansByStatus))
+	for status := range s.scansByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		mw.sample("restuss_scans", float64(s.scansByStatus[status]), "status", status)
+	}
+
+	if s.lastCompleted > 0 {
+		mw.family("restuss_seconds_since_last_completed_scan", "gauge", "Time since the last scan completed.")
+		mw.sample("restuss_seconds_since_last_completed_scan", now.Sub(time.Unix(s.lastCompleted, 0)).Seconds())
+	}
+
+	mw.family("restuss_scan_vulnerabilities", "gauge", "Vulnerabilities found by the last run of a scan, by severity.")
+	for _, sc := range s.scans {
+		id := strconv.FormatInt(sc.scan.ID, 10)
+		for sev, n := range sc.counts {
+			mw.sample("restuss_scan_vulnerabilities", float64(n),
+				"scan_id", id, "scan_name", sc.scan.Name, "severity", severityNames[sev])
+		}
+	}
+
+	if len(s.assets) > 0 {
+		mw.family("restuss_asset_open_findings", "gauge", "Open findings of an asset, by severity.")
+		assets := make([]string, 0, len(s.assets))
+		for a := range s.assets {
+			assets = append(assets, a)
+		}
+		sort.Strings(assets)
+		for _, a := range assets {
+			for sev, n := range s.assets[a] {
+				mw.sample("restuss_asset_open_findings", float64(n), "asset", a, "severity", severityNames[sev])
+			}
+		}
+	}
+
+	if e.opts.Scanners {
+		mw.family("restuss_scanner_up", "gauge", "Whether a scanner is online.")
+		for _, sc := range s.scanners {
+			mw.sample("restuss_scanner_up", boolFloat(sc.Status == "on"), scannerLabels(sc)...)
+		}
+		mw.family("restuss_scanner_running_scans", "gauge", "Scans running on a scanner.")
+		for _, sc := range s.scanners {
+			mw.sample("restuss_scanner_running_scans", float64(sc.NumScans), scannerLabels(sc)...)
+		}
+		mw.family("restuss_scanner_last_connect_timestamp_seconds", "gauge", "Time a scanner last connected.")
+		for _, sc := range s.scanners {
+			mw.sample("restuss_scanner_last_connect_timestamp_seconds", float64(sc.LastConnect), scannerLabels(sc)...)
+		}
+	}
+}
+
+func scannerLabels(s Scanner) []string {
+	return []string{"scanner_id", strconv.FormatInt(s.ID, 10), "scanner_name", s.Name}
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package restuss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExporter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/scans":
+			fmt.Fprint(w, `{
+				"folders": [{"id": 2, "type": "trash"}],
+				"scans": [
+					{"id": 1, "name": "web", "status": "completed", "folder_id": 3, "last_modification_date": 1700000000},
+					{"id": 2, "name": "db", "status": "running", "folder_id": 3},
+					{"id": 3, "name": "old", "status": "completed", "folder_id": 2}
+				]
+			}`)
+		case "/scans/1", "/scans/2":
+			fmt.Fprint(w, `{"vulnerabilities": [
+				{"plugin_id": 10, "severity": 4, "count": 2},
+				{"plugin_id": 11, "severity": 4, "count": 1},
+				{"plugin_id": 12, "severity": 1, "count": 5}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(NewKeyAuthProvider("a", "s"), ts.URL, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	e := NewExporter(c, ExporterOptions{ScanIDs: []int64{1}})
+	err = e.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+	for _, line := range []string{
+		`restuss_exporter_last_collect_success 1`,
+		`restuss_scans{status="completed"} 1`,
+		`restuss_scans{status="running"} 1`,
+		`restuss_scan_vulnerabilities{scan_id="1",scan_name="web",severity="critical"} 3`,
+		`restuss_scan_vulnerabilities{scan_id="1",scan_name="web",severity="low"} 5`,
+		`restuss_client_requests_total{method="GET",code="200"} 2`,
+		`restuss_client_request_duration_seconds_count{method="GET"} 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("metrics lack %q:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `scan_id="2"`) {
+		t.Fatalf("metrics include a scan not selected:\n%s", out)
+	}
+}
@@ -0,0 +1,128 @@
+package restuss
+
+import (
+	"strings"
+	"time"
+)
+
+// Finding states
+const (
+	FindingStateOpen     = "open"
+	FindingStateReopened = "reopened"
+	FindingStateFixed    = "fixed"
+)
+
+// FindingsOptions restricts the findings returned by findings searches
+type FindingsOptions struct {
+	// NetworkID restricts the search to a network, all networks are
+	// searched when empty.
+	NetworkID string
+	// States restricts the search to findings in any of the given states.
+	States []string
+	// Fields selects the attributes returned, FindingFieldsDefault when empty.
+	Fields FindingFields
+}
+
+// FindingFields lists the attributes requested in findings searches
+type FindingFields []string
+
+// With returns a copy of the fields with the given ones added
+func (f FindingFields) With(fields ...string) FindingFields {
+	out := make(FindingFields, 0, len(f)+len(fields))
+	out = append(out, f...)
+	for _, field := range fields {
+		found := false
+		for _, existing := range out {
+			if existing == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// Presets of findings fields
+var (
+	// FindingFieldsMinimal identifies the finding and its plugin.
+	FindingFieldsMinimal = FindingFields{
+		"id",
+		"severity",
+		"port",
+		"protocol",
+		"plugin_id",
+		"name",
+		"state",
+	}
+	// FindingFieldsDefault are the fields used when none are selected.
+	FindingFieldsDefault = FindingFields{
+		"output",
+		"id",
+		"severity",
+		"port",
+		"protocol",
+		"service",
+		"plugin_id",
+		"name",
+		"description",
+		"synopsis",
+		"cvss3_base_score",
+		"cvss2_base_score",
+		"cwe",
+		"see_also",
+		"state",
+		"first_found",
+		"last_found",
+		"last_fixed",
+	}
+	// FindingFieldsFull adds asset, exploitability and risk attributes.
+	FindingFieldsFull = FindingFieldsDefault.With(
+		"solution",
+		"asset.id",
+		"asset.name",
+		"asset.ipv4",
+		"asset.ipv6",
+		"asset.fqdn",
+		"plugin.cve",
+		"plugin.exploit_available",
+		"cvss3_vector",
+		"cvss2_vector",
+		"vpr_score",
+		"risk_modified",
+	)
+)
+
+// RemediationStats represents the time taken to fix findings of a severity
+type RemediationStats struct {
+	Fixed int
+	Mean  time.Duration
+}
+
+// MeanTimeToRemediate returns, per severity, the mean time between the first
+// time a finding was found and the last time it was fixed. Findings not fixed
+// are ignored.
+func MeanTimeToRemediate(findings []Finding) map[int]RemediationStats {
+	totals := map[int]time.Duration{}
+	stats := map[int]RemediationStats{}
+	for _, f := range findings {
+		if !strings.EqualFold(f.State, FindingStateFixed) || f.FirstFound == nil || f.LastFixed == nil {
+			continue
+		}
+		d := f.LastFixed.Sub(*f.FirstFound)
+		if d < 0 {
+			continue
+		}
+		totals[f.Severity] += d
+		s := stats[f.Severity]
+		s.Fixed++
+		stats[f.Severity] = s
+	}
+	for severity, s := range stats {
+		s.Mean = totals[severity] / time.Duration(s.Fixed)
+		stats[severity] = s
+	}
+	return stats
+}
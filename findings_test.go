@@ -0,0 +1,63 @@
+package restuss
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMeanTimeToRemediate(t *testing.T) {
+	day := 24 * time.Hour
+	at := func(d int) *time.Time {
+		t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(d) * day)
+		return &t
+	}
+
+	findings := []Finding{
+		{Severity: 4, State: "FIXED", FirstFound: at(0), LastFixed: at(2)},
+		{Severity: 4, State: FindingStateFixed, FirstFound: at(0), LastFixed: at(4)},
+		{Severity: 4, State: FindingStateOpen, FirstFound: at(0)},
+		{Severity: 2, State: FindingStateFixed, FirstFound: at(1), LastFixed: at(11)},
+		{Severity: 1, State: FindingStateReopened, FirstFound: at(1), LastFixed: at(3)},
+	}
+
+	got := MeanTimeToRemediate(findings)
+	want := map[int]RemediationStats{
+		4: {Fixed: 2, Mean: 3 * day},
+		2: {Fixed: 1, Mean: 10 * day},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got: %v, expected: %v", got, want)
+	}
+	for severity, s := range want {
+		if got[severity] != s {
+			t.Fatalf("severity %d got: %v, expected: %v", severity, got[severity], s)
+		}
+	}
+}
+
+func TestFindingUnmarshalExtra(t *testing.T) {
+	data := []byte(`{
+		"id": "f1",
+		"severity": 3,
+		"asset": {"id": "a1", "ipv4": "10.0.0.1"},
+		"definition": {"id": 19506, "cve": ["CVE-2024-0001"], "exploit_available": true, "vpr_v2": {"score": 7.1}},
+		"custom_attribute": {"x": 1}
+	}`)
+
+	var f Finding
+	err := json.Unmarshal(data, &f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.ID != "f1" || f.Asset.ID != "a1" || len(f.Asset.IPv4) != 1 || f.Asset.IPv4[0] != "10.0.0.1" {
+		t.Fatalf("Unexpected finding: %+v", f)
+	}
+	if len(f.Definition.CVE) != 1 || f.Definition.ExploitAvailable == nil || !*f.Definition.ExploitAvailable {
+		t.Fatalf("Unexpected definition: %+v", f.Definition)
+	}
+	if len(f.Extra) != 2 || string(f.Extra["custom_attribute"]) != `{"x": 1}` ||
+		string(f.Extra["definition.vpr_v2"]) != `{"score": 7.1}` {
+		t.Fatalf("Unexpected extra attributes: %v", f.Extra)
+	}
+}
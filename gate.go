@@ -0,0 +1,403 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// severityNames are the lower case severity names, indexed by Nessus severity
+var severityNames = []string{"info", "low", "medium", "high", "critical"}
+
+// GatePolicy decides whether the findings of a scan are acceptable:
+//
+//	{
+//		"max_counts": {"critical": 0, "high": 2},
+//		"cvss_floor": 4.0,
+//		"allowlist": [{"plugin_id": 51192, "expires": "2026-12-31", "reason": "self-signed staging cert"}],
+//		"ignored_ports": [8080]
+//	}
+type GatePolicy struct {
+	// MaxCounts maps severity names ("critical", "high", "medium", "low"
+	// and "info") to the maximum number of findings allowed, severities
+	// not listed are unlimited.
+	MaxCounts map[string]int `json:"max_counts"`
+	// CVSSFloor ignores the findings with a CVSS base score, v3 or else
+	// v2, below it. Findings without score are always evaluated.
+	CVSSFloor float32 `json:"cvss_floor"`
+	// Allowlist lists the plugins whose findings are accepted
+	Allowlist []GateAllowance `json:"allowlist"`
+	// IgnoredPorts lists the ports whose findings are ignored
+	IgnoredPorts []int `json:"ignored_ports"`
+}
+
+// GateAllowance accepts the findings of a plugin until it expires
+type GateAllowance struct {
+	PluginID int64 `json:"plugin_id"`
+	// Expires is the day, formatted as 2006-01-02, from which the findings
+	// are no longer accepted. Allowances without expiry never expire.
+	Expires string `json:"expires"`
+	Reason  string `json:"reason"`
+}
+
+// Status of the findings evaluated by a gate
+const (
+	GateCounted = "counted"
+	GateAllowed = "allowed"
+	GateIgnored = "ignored"
+)
+
+// GateFinding represents a finding evaluated by a gate
+type GateFinding struct {
+	HostFinding
+	// Status is one of GateCounted, GateAllowed or GateIgnored
+	Status string
+	// Reason explains why the finding was allowed or ignored, or notes that
+	// its allowance expired
+	Reason string
+}
+
+// GateViolation represents a severity with more findings than allowed
+type GateViolation struct {
+	Severity int
+	Count    int
+	Max      int
+}
+
+// GateResult represents the result of the evaluation of findings by a gate
+type GateResult struct {
+	Findings []GateFinding
+	// Counts holds the number of counted findings, indexed by severity
+	Counts     [5]int
+	Violations []GateViolation
+	policy     *GatePolicy
+}
+
+// Passed reports whether no severity has more findings than allowed
+func (r *GateResult) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// LoadGatePolicy reads a gate policy in JSON, rejecting unknown attributes,
+// severities and malformed expiry dates
+func LoadGatePolicy(r io.Reader) (*GatePolicy, error) {
+	p := &GatePolicy{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(p)
+	if err != nil {
+		return nil, errors.New("Unable to parse gate policy: " + err.Error())
+	}
+
+	for name := range p.MaxCounts {
+		if gateSeverity(name) < 0 {
+			return nil, errors.New("Unknown severity in gate policy: " + name)
+		}
+	}
+	for _, a := range p.Allowlist {
+		if a.Expires == "" {
+			continue
+		}
+		_, err = time.Parse("2006-01-02", a.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid expiry date of plugin %d in gate policy: %s", a.PluginID, a.Expires)
+		}
+	}
+	return p, nil
+}
+
+// Evaluate evaluates findings against the policy at the given time, which
+// expires allowances
+func (p *GatePolicy) Evaluate(findings []HostFinding, now time.Time) *GateResult {
+	ignoredPorts := map[int]bool{}
+	for _, port := range p.IgnoredPorts {
+		ignoredPorts[port] = true
+	}
+	allowances := map[int64]GateAllowance{}
+	for _, a := range p.Allowlist {
+		allowances[a.PluginID] = a
+	}
+
+	r := &GateResult{policy: p}
+	for _, f := range findings {
+		gf := GateFinding{HostFinding: f, Status: GateCounted}
+		score := f.CVSS3
+		if score == nil {
+			score = f.CVSS2
+		}
+
+		a, allowed := allowances[f.PluginID]
+		switch {
+		case ignoredPorts[f.Port]:
+			gf.Status = GateIgnored
+			gf.Reason = "port " + strconv.Itoa(f.Port) + " is ignored"
+		case score != nil && *score < p.CVSSFloor:
+			gf.Status = GateIgnored
+			gf.Reason = fmt.Sprintf("CVSS %.1f is below %.1f", *score, p.CVSSFloor)
+		case allowed && !allowanceExpired(a, now):
+			gf.Status = GateAllowed
+			gf.Reason = a.Reason
+		case allowed:
+			gf.Reason = "allowance expired on " + a.Expires
+		}
+
+		if gf.Status == GateCounted && f.Severity >= 0 && f.Severity < len(r.Counts) {
+			r.Counts[f.Severity]++
+		}
+		r.Findings = append(r.Findings, gf)
+	}
+
+	for sev := len(r.Counts) - 1; sev >= 0; sev-- {
+		max, ok := p.maxCount(sev)
+		if ok && r.Counts[sev] > max {
+			r.Violations = append(r.Violations, GateViolation{Severity: sev, Count: r.Counts[sev], Max: max})
+		}
+	}
+	return r
+}
+
+// GateOptions configures RunGate
+type GateOptions struct {
+	// AltTargets, when not empty, replaces the targets of the scan
+	AltTargets []string
+	// PollInterval is how often the status of the scan is checked, 30
+	// seconds by default
+	PollInterval time.Duration
+}
+
+// RunGate launches a scan, waits for it to complete and evaluates its
+// findings against the policy. It waits as long as the scan runs, use
+// RunGateContext to set a deadline.
+func (c *NessusClient) RunGate(scanID int64, policy *GatePolicy, opts GateOptions) (*GateResult, error) {
+	return c.RunGateContext(context.Background(), scanID, policy, opts)
+}
+
+// RunGateContext launches a scan, waits for it to complete and evaluates its
+// findings against the policy using the given context. There is no timeout,
+// so the context should have a deadline for the gate not to wait forever for
+// a scan which never completes. A *ScanFailedError is returned when the scan
+// is canceled or aborted.
+func (c *NessusClient) RunGateContext(ctx context.Context, scanID int64, policy *GatePolicy, opts GateOptions) (*GateResult, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+
+	uuid, err := c.LaunchScanWithTargetsContext(ctx, scanID, opts.AltTargets)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to launch scan: %w", err)
+	}
+
+	// The status of the scan may still be the one of the previous run, so
+	// the run launched is looked up in the history.
+	var historyID int64
+	for {
+		detail, err := c.GetScanByIDContext(ctx, scanID)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range detail.History {
+			if h.UUID != uuid {
+				continue
+			}
+			switch h.Status {
+			case "completed":
+				historyID = h.HistoryID
+			case "canceled", "aborted":
+				return nil, &ScanFailedError{ScanID: scanID, Status: h.Status}
+			}
+		}
+		if historyID != 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+
+	findings, err := c.GetHostFindingsContext(ctx, scanID, historyID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get scan findings: %w", err)
+	}
+	return policy.Evaluate(findings, time.Now()), nil
+}
+
+// WriteSummary writes a human readable summary of the result to w
+func (r *GateResult) WriteSummary(w io.Writer) error {
+	var b strings.Builder
+	if r.Passed() {
+		b.WriteString("Gate passed\n")
+	} else {
+		b.WriteString("Gate FAILED\n")
+	}
+
+	for sev := len(r.Counts) - 1; sev >= 0; sev-- {
+		fmt.Fprintf(&b, "  %-8s %d", severityNames[sev], r.Counts[sev])
+		if max, ok := r.policy.maxCount(sev); ok {
+			fmt.Fprintf(&b, " (max %d)", max)
+			if r.Counts[sev] > max {
+				b.WriteString(" VIOLATION")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	var allowed, ignored int
+	for _, f := range r.Findings {
+		switch {
+		case f.Status == GateAllowed:
+			allowed++
+		case f.Status == GateIgnored:
+			ignored++
+		case f.Reason != "":
+			fmt.Fprintf(&b, "  warning: plugin %d on %s: %s\n", f.PluginID, f.Host, f.Reason)
+		}
+	}
+	fmt.Fprintf(&b, "%d findings evaluated, %d allowed, %d ignored\n", len(r.Findings), allowed, ignored)
+
+	for _, f := range r.Findings {
+		if r.violates(f) {
+			fmt.Fprintf(&b, "  %s %s:%d/%s plugin %d %s\n",
+				severityNames[f.Severity], f.Host, f.Port, f.Protocol, f.PluginID, f.PluginName)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the result to w as a JUnit XML report, with a test case
+// per severity limit and per finding. Findings of severities over their
+// limit fail, allowed and ignored ones are skipped.
+func (r *GateResult) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{Name: "nessus-gate"}
+
+	for sev := len(r.Counts) - 1; sev >= 0; sev-- {
+		max, ok := r.policy.maxCount(sev)
+		if !ok {
+			continue
+		}
+		tc := junitTestCase{
+			ClassName: "severity",
+			Name:      fmt.Sprintf("at most %d %s findings", max, severityNames[sev]),
+		}
+		if r.Counts[sev] > max {
+			tc.Failure = &junitMessage{Message: fmt.Sprintf("%d %s findings", r.Counts[sev], severityNames[sev])}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, f := range r.Findings {
+		tc := junitTestCase{
+			ClassName: f.Host,
+			Name:      fmt.Sprintf("%d %s (%d/%s)", f.PluginID, f.PluginName, f.Port, strings.ToLower(f.Protocol)),
+		}
+		switch {
+		case f.Status != GateCounted:
+			tc.Skipped = &junitMessage{Message: f.Status + ": " + f.Reason}
+		case r.violates(f):
+			tc.Failure = &junitMessage{
+				Message: severityNames[f.Severity] + " finding over the limit",
+				Text:    f.Output,
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, tc := range suite.Cases {
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		if tc.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// violates reports whether a finding is counted in a severity over its limit
+func (r *GateResult) violates(f GateFinding) bool {
+	if f.Status != GateCounted {
+		return false
+	}
+	for _, v := range r.Violations {
+		if v.Severity == f.Severity {
+			return true
+		}
+	}
+	return false
+}
+
+// maxCount returns the maximum number of findings of a severity, severity
+// names being case insensitive
+func (p *GatePolicy) maxCount(severity int) (int, bool) {
+	for name, max := range p.MaxCounts {
+		if gateSeverity(name) == severity {
+			return max, true
+		}
+	}
+	return 0, false
+}
+
+func allowanceExpired(a GateAllowance, now time.Time) bool {
+	if a.Expires == "" {
+		return false
+	}
+	expires, err := time.ParseInLocation("2006-01-02", a.Expires, now.Location())
+	return err != nil || !now.Before(expires)
+}
+
+func gateSeverity(name string) int {
+	for i, s := range severityNames {
+		if s == strings.ToLower(name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,85 @@
+package restuss_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adevinta/restuss"
+	"github.com/adevinta/restuss/restusstest"
+)
+
+func TestRunGate(t *testing.T) {
+	tests := []struct {
+		name string
+		// launched is run once the scan is launched
+		launched   func(s *restusstest.Server, scanID int64)
+		violations int
+		status     string
+	}{
+		{
+			name: "completed",
+			launched: func(s *restusstest.Server, scanID int64) {
+				s.Clock.Advance(time.Hour)
+			},
+			violations: 1,
+		},
+		{
+			name: "canceled",
+			launched: func(s *restusstest.Server, scanID int64) {
+				_ = s.Client().StopScan(scanID)
+			},
+			status: "canceled",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := restusstest.NewServer()
+			defer s.Close()
+			c := s.Client()
+
+			scan, err := c.CreateScan(&restuss.Scan{
+				TemplateUUID: restusstest.TemplateBasic,
+				Settings:     restuss.ScanSettings{Name: "staging", Targets: "a.example.com"},
+			})
+			if err != nil {
+				t.Fatalf("Error creating scan: %v", err)
+			}
+			s.SetResults(scan.ID, []restuss.HostFinding{
+				{Host: "a.example.com", PluginID: 10, PluginName: "Old server", Port: 80, Protocol: "tcp", Severity: 3},
+				{Host: "a.example.com", PluginID: 20, PluginName: "SSH banner", Port: 22, Protocol: "tcp", Severity: 1},
+			})
+			c.SetHooks(&restuss.Hooks{OnResponse: func(e *restuss.RequestEvent) {
+				if e.Endpoint == "scans.launch" {
+					tc.launched(s, scan.ID)
+				}
+			}})
+			policy, err := restuss.LoadGatePolicy(strings.NewReader(`{"max_counts": {"high": 0}}`))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// The scan status is checked before waiting for the poll interval.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			r, err := c.RunGateContext(ctx, scan.ID, policy, restuss.GateOptions{PollInterval: time.Hour})
+			if tc.status != "" {
+				var failed *restuss.ScanFailedError
+				if !errors.As(err, &failed) || failed.ScanID != scan.ID || failed.Status != tc.status {
+					t.Fatalf("got error: %v, expected the scan %s", err, tc.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if r.Passed() || len(r.Violations) != tc.violations || r.Violations[0].Count != 1 {
+				t.Fatalf("got violations: %+v, expected 1 high finding", r.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package restuss
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGatePolicyEvaluate(t *testing.T) {
+	policy, err := LoadGatePolicy(strings.NewReader(`{
+		"max_counts": {"Critical": 0, "high": 1},
+		"cvss_floor": 4.0,
+		"allowlist": [
+			{"plugin_id": 10, "expires": "2026-12-31", "reason": "accepted"},
+			{"plugin_id": 20, "expires": "2026-01-01"}
+		],
+		"ignored_ports": [8080]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	low := float32(3.1)
+	findings := []HostFinding{
+		{Host: "a", PluginID: 10, Severity: 4, Port: 443},
+		{Host: "a", PluginID: 20, Severity: 3, Port: 443},
+		{Host: "a", PluginID: 30, Severity: 3, Port: 443},
+		{Host: "a", PluginID: 40, Severity: 4, Port: 8080},
+		{Host: "a", PluginID: 50, Severity: 3, Port: 22, CVSS3: &low},
+	}
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := policy.Evaluate(findings, now)
+
+	statuses := []string{GateAllowed, GateCounted, GateCounted, GateIgnored, GateIgnored}
+	for i, s := range statuses {
+		if r.Findings[i].Status != s {
+			t.Fatalf("got status %s for plugin %d, expected %s", r.Findings[i].Status, r.Findings[i].PluginID, s)
+		}
+	}
+	if r.Passed() || len(r.Violations) != 1 || r.Violations[0].Severity != 3 || r.Violations[0].Count != 2 {
+		t.Fatalf("got violations: %+v, expected 2 high findings", r.Violations)
+	}
+
+	var buf bytes.Buffer
+	err = r.WriteJUnit(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `tests="7" failures="3" skipped="3"`) {
+		t.Fatalf("unexpected JUnit report:\n%s", buf.String())
+	}
+}
+
+func TestLoadGatePolicyErrors(t *testing.T) {
+	for _, p := range []string{
+		`{"max_counts": {"severe": 0}}`,
+		`{"allowlist": [{"plugin_id": 1, "expires": "31/12/2026"}]}`,
+		`{"max_count": {"critical": 0}}`,
+	} {
+		_, err := LoadGatePolicy(strings.NewReader(p))
+		if err == nil {
+			t.Errorf("expected error loading %s", p)
+		}
+	}
+}
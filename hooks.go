@@ -0,0 +1,229 @@
+package restuss
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Hooks are callbacks run around the HTTP requests made by a client, to trace
+// or measure them. Any of them can be nil. They are run synchronously, in the
+// goroutine making the call.
+type Hooks struct {
+	// OnRequest is run before each attempt of a call
+	OnRequest func(e *RequestEvent)
+	// OnResponse is run when an attempt receives a response, successful or
+	// not
+	OnResponse func(e *RequestEvent)
+	// OnRetry is run before waiting to try a call again
+	OnRetry func(e *RequestEvent)
+	// OnError is run once when a call fails, whether no response was
+	// received, the retry limit was exceeded or the response couldn't be read
+	OnError func(e *RequestEvent)
+}
+
+// RequestEvent describes a call to the API. The same event is passed to all
+// the hooks run for a call, its fields being updated at every attempt.
+type RequestEvent struct {
+	// Endpoint is the name of the endpoint called, such as "scans.launch",
+	// see EndpointName
+	Endpoint string
+	Method   string
+	URL      *url.URL
+	// Attempt is the number of the current attempt, starting at 1
+	Attempt int
+	// StatusCode is the status code of the response to the attempt, zero
+	// when none was received
+	StatusCode int
+	// Duration is the time the attempt took to receive a response
+	Duration time.Duration
+	// Wait is the time waited before trying again, set for OnRetry
+	Wait time.Duration
+	// Err is the error the call failed with, set for OnError
+	Err error
+	// Context is the context of the call. OnRequest can replace it, for
+	// instance with one holding a tracing span, and the attempt is made with
+	// the new context.
+	Context context.Context
+}
+
+// SetHooks makes the client run h around its requests, nil disabling it
+func (c *NessusClient) SetHooks(h *Hooks) {
+	c.hooks = h
+}
+
+// route maps requests to an endpoint name, "*" matching any path segment
+type route struct {
+	method string
+	path   string
+	name   string
+}
+
+// routes lists the endpoints called by the client. Literal segments are
+// listed before the wildcards they would otherwise match.
+var routes = []route{
+	{http.MethodGet, "/scans", "scans.list"},
+	{http.MethodPost, "/scans", "scans.create"},
+	{http.MethodGet, "/scans/*", "scans.details"},
+	{http.MethodDelete, "/scans/*", "scans.delete"},
+	{http.MethodPost, "/scans/*/launch", "scans.launch"},
+	{http.MethodPost, "/scans/*/stop", "scans.stop"},
+	{http.MethodPost, "/scans/*/pause", "scans.pause"},
+	{http.MethodPost, "/scans/*/resume", "scans.resume"},
+	{http.MethodPost, "/scans/*/export", "scans.export_request"},
+	{http.MethodGet, "/scans/*/export/*/status", "scans.export_status"},
+	{http.MethodGet, "/scans/*/export/*/download", "scans.export_download"},
+	{http.MethodGet, "/scans/*/hosts/*", "scans.host_details"},
+	{http.MethodGet, "/scans/*/hosts/*/plugins/*", "scans.plugin_output"},
+	{http.MethodGet, "/editor/scan/templates", "editor.list"},
+	{http.MethodGet, "/plugins/plugin/*", "plugins.plugin_details"},
+	{http.MethodGet, "/policies/*", "policies.details"},
+	{http.MethodPut, "/policies/*", "policies.configure"},
+	{http.MethodGet, "/policies/*/export", "policies.export"},
+	{http.MethodPost, "/api/v3/assets/search", "assets.search"},
+	{http.MethodPost, "/api/v3/findings/vulnerabilities/host/search", "findings.search"},
+	{http.MethodGet, "/scanners", "scanners.list"},
+	{http.MethodGet, "/scanners/*", "scanners.details"},
+	{http.MethodGet, "/scanners/*/scans", "scanners.get_scans"},
+	{http.MethodGet, "/scanners/*/agents", "agents.list"},
+	{http.MethodPost, "/scanners/*/agents/_bulk/unlink", "agents.bulk_unlink"},
+	{http.MethodGet, "/scanners/*/agents/_bulk/*", "agents.bulk_status"},
+	{http.MethodGet, "/scanners/*/agents/*", "agents.get"},
+	{http.MethodDelete, "/scanners/*/agents/*", "agents.delete"},
+	{http.MethodGet, "/scanners/*/agent-groups", "agent_groups.list"},
+	{http.MethodPost, "/scanners/*/agent-groups", "agent_groups.create"},
+	{http.MethodGet, "/scanners/*/agent-groups/*", "agent_groups.details"},
+	{http.MethodPut, "/scanners/*/agent-groups/*", "agent_groups.configure"},
+	{http.MethodDelete, "/scanners/*/agent-groups/*", "agent_groups.delete"},
+	{http.MethodPost, "/scanners/*/agent-groups/*/agents/_bulk/add", "agent_groups.bulk_add"},
+	{http.MethodPost, "/scanners/*/agent-groups/*/agents/_bulk/remove", "agent_groups.bulk_remove"},
+	{http.MethodGet, "/scanners/*/agent-groups/*/agents/_bulk/*", "agent_groups.bulk_status"},
+	{http.MethodPut, "/scanners/*/agent-groups/*/agents/*", "agent_groups.add_agent"},
+	{http.MethodDelete, "/scanners/*/agent-groups/*/agents/*", "agent_groups.delete_agent"},
+	{http.MethodGet, "/scanner-groups", "scanner_groups.list"},
+	{http.MethodPost, "/scanner-groups", "scanner_groups.create"},
+	{http.MethodGet, "/scanner-groups/*", "scanner_groups.details"},
+	{http.MethodPut, "/scanner-groups/*", "scanner_groups.edit"},
+	{http.MethodDelete, "/scanner-groups/*", "scanner_groups.delete"},
+	{http.MethodGet, "/scanner-groups/*/scanners", "scanner_groups.list_scanners"},
+	{http.MethodPost, "/scanner-groups/*/scanners/*", "scanner_groups.add_scanner"},
+	{http.MethodDelete, "/scanner-groups/*/scanners/*", "scanner_groups.delete_scanner"},
+	{http.MethodGet, "/networks", "networks.list"},
+	{http.MethodPost, "/networks", "networks.create"},
+	{http.MethodGet, "/networks/*", "networks.details"},
+	{http.MethodPut, "/networks/*", "networks.update"},
+	{http.MethodDelete, "/networks/*", "networks.delete"},
+	{http.MethodGet, "/networks/*/scanners", "networks.list_scanners"},
+	{http.MethodPost, "/networks/*/scanners", "networks.assign_scanners"},
+	{http.MethodGet, "/credentials", "credentials.list"},
+	{http.MethodPost, "/credentials", "credentials.create"},
+	{http.MethodGet, "/credentials/types", "credentials.types"},
+	{http.MethodGet, "/credentials/*", "credentials.details"},
+	{http.MethodPut, "/credentials/*", "credentials.update"},
+	{http.MethodDelete, "/credentials/*", "credentials.delete"},
+	{http.MethodGet, "/exclusions", "exclusions.list"},
+	{http.MethodPost, "/exclusions", "exclusions.create"},
+	{http.MethodGet, "/exclusions/*", "exclusions.details"},
+	{http.MethodPut, "/exclusions/*", "exclusions.edit"},
+	{http.MethodDelete, "/exclusions/*", "exclusions.delete"},
+	{http.MethodGet, "/target-groups", "target_groups.list"},
+	{http.MethodPost, "/target-groups", "target_groups.create"},
+	{http.MethodGet, "/target-groups/*", "target_groups.details"},
+	{http.MethodPut, "/target-groups/*", "target_groups.edit"},
+	{http.MethodDelete, "/target-groups/*", "target_groups.delete"},
+	{http.MethodGet, "/tags/categories", "tags.list_categories"},
+	{http.MethodPost, "/tags/categories", "tags.create_category"},
+	{http.MethodGet, "/tags/values", "tags.list_values"},
+	{http.MethodPost, "/tags/values", "tags.create_value"},
+	{http.MethodDelete, "/tags/values/*", "tags.delete_value"},
+	{http.MethodPost, "/tags/assets/assignments", "tags.edit_asset_assignments"},
+	{http.MethodGet, "/tags/assets/assignments/*", "tags.asset_assignments_status"},
+	{http.MethodGet, "/users", "users.list"},
+	{http.MethodPost, "/users", "users.create"},
+	{http.MethodPut, "/users/*", "users.edit"},
+	{http.MethodDelete, "/users/*", "users.delete"},
+	{http.MethodPut, "/users/*/chpasswd", "users.password"},
+	{http.MethodPut, "/users/*/keys", "users.keys"},
+	{http.MethodGet, "/groups", "groups.list"},
+	{http.MethodPost, "/groups", "groups.create"},
+	{http.MethodPut, "/groups/*", "groups.edit"},
+	{http.MethodDelete, "/groups/*", "groups.delete"},
+	{http.MethodGet, "/groups/*/users", "groups.list_users"},
+	{http.MethodPost, "/groups/*/users/*", "groups.add_user"},
+	{http.MethodDelete, "/groups/*/users/*", "groups.delete_user"},
+	{http.MethodGet, "/permissions/*/*", "permissions.list"},
+	{http.MethodPut, "/permissions/*/*", "permissions.change"},
+	{http.MethodGet, "/workbenches/vulnerabilities", "workbenches.vulnerabilities"},
+	{http.MethodGet, "/workbenches/vulnerabilities/*/info", "workbenches.vulnerability_info"},
+	{http.MethodGet, "/workbenches/assets", "workbenches.assets"},
+	{http.MethodGet, "/workbenches/assets/*/vulnerabilities", "workbenches.asset_vulnerabilities"},
+	{http.MethodGet, "/workbenches/export", "workbenches.export_request"},
+	{http.MethodGet, "/workbenches/export/*/status", "workbenches.export_status"},
+	{http.MethodGet, "/workbenches/export/*/download", "workbenches.export_download"},
+	{http.MethodPost, "/compliance/export", "compliance.export_request"},
+	{http.MethodGet, "/compliance/export/*/status", "compliance.export_status"},
+	{http.MethodGet, "/compliance/export/*/chunks/*", "compliance.export_download"},
+	{http.MethodGet, "/audit-log/v1/events", "audit_log.events"},
+}
+
+// EndpointName returns the name of the endpoint a request with the given
+// method and path, relative to the URL of the API, calls, such as
+// "scans.launch" for a POST to /scans/42/launch. The names follow those of
+// the Tenable.io API documentation and don't depend on the IDs in the path,
+// so they can label metrics and traces. Unknown endpoints are named after
+// their method and first path segment, such as "get.scans".
+func EndpointName(method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, r := range routes {
+		if r.method == method && matchRoute(r.path, segments) {
+			return r.name
+		}
+	}
+	return strings.ToLower(method) + "." + segments[0]
+}
+
+func matchRoute(pattern string, segments []string) bool {
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(parts) != len(segments) {
+		return false
+	}
+	for i, p := range parts {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// endpoint returns the name of the endpoint req calls
+func (c *NessusClient) endpoint(req *http.Request) string {
+	path := req.URL.Path
+	base, err := url.Parse(c.url)
+	if err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+	return EndpointName(req.Method, path)
+}
+
+// newRequestEvent returns the event describing a call made with req to the
+// endpoint, nil when the client has no hooks
+func (c *NessusClient) newRequestEvent(req *http.Request, endpoint string) *RequestEvent {
+	if c.hooks == nil {
+		return nil
+	}
+	return &RequestEvent{
+		Endpoint: endpoint,
+		Method:   req.Method,
+		URL:      req.URL,
+		Context:  req.Context(),
+	}
+}
+
+// run runs the hook, if any, with the event
+func (e *RequestEvent) run(hook func(*RequestEvent)) {
+	if e != nil && hook != nil {
+		hook(e)
+	}
+}
@@ -0,0 +1,188 @@
+package restuss
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency
+// histogram buckets
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// ClientMetrics counts the HTTP requests made by clients, each attempt of a
+// call being a request. It is safe for concurrent use and can be shared by
+// several clients.
+type ClientMetrics struct {
+	mu          sync.Mutex
+	requests    map[[2]string]uint64
+	retries     uint64
+	rateLimited uint64
+	latency     map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewClientMetrics returns an empty ClientMetrics
+func NewClientMetrics() *ClientMetrics {
+	return &ClientMetrics{
+		requests: map[[2]string]uint64{},
+		latency:  map[string]*histogram{},
+	}
+}
+
+// SetMetrics makes the client count its requests in m, nil disabling it. It
+// must be called before the client is used.
+func (c *NessusClient) SetMetrics(m *ClientMetrics) {
+	c.metrics = m
+}
+
+// observe counts a request, code being the status code or "error" when no
+// response was received
+func (m *ClientMetrics) observe(method, code string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[[2]string{method, code}]++
+	if code == "429" {
+		m.rateLimited++
+	}
+	h, ok := m.latency[method]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latency[method] = h
+	}
+	s := d.Seconds()
+	for i, b := range latencyBuckets {
+		if s <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += s
+	h.count++
+}
+
+// retry counts a request tried again, the last failed attempt of a call not
+// being counted
+func (m *ClientMetrics) retry() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries++
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format
+func (m *ClientMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mw := &metricWriter{w: w}
+	mw.family("restuss_client_requests_total", "counter", "HTTP requests made to the API, by method and status code.")
+	keys := make([][2]string, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		mw.sample("restuss_client_requests_total", float64(m.requests[k]), "method", k[0], "code", k[1])
+	}
+
+	mw.family("restuss_client_retries_total", "counter", "Requests tried again after a non-successful status code.")
+	mw.sample("restuss_client_retries_total", float64(m.retries))
+	mw.family("restuss_client_rate_limited_total", "counter", "Requests rejected with status code 429.")
+	mw.sample("restuss_client_rate_limited_total", float64(m.rateLimited))
+
+	const latency = "restuss_client_request_duration_seconds"
+	mw.family(latency, "histogram", "Latency of the HTTP requests made to the API, by method.")
+	methods := make([]string, 0, len(m.latency))
+	for method := range m.latency {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		h := m.latency[method]
+		for i, b := range latencyBuckets {
+			mw.sample(latency+"_bucket", float64(h.counts[i]), "method", method, "le", formatFloat(b))
+		}
+		mw.sample(latency+"_bucket", float64(h.count), "method", method, "le", "+Inf")
+		mw.sample(latency+"_sum", h.sum, "method", method)
+		mw.sample(latency+"_count", float64(h.count), "method", method)
+	}
+
+	return mw.n, mw.err
+}
+
+// metricWriter writes metrics in the Prometheus text format, keeping the
+// first error
+type metricWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (mw *metricWriter) printf(format string, args ...interface{}) {
+	if mw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(mw.w, format, args...)
+	mw.n += int64(n)
+	mw.err = err
+}
+
+func (mw *metricWriter) family(name, typ, help string) {
+	mw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample with the given label names and values, as pairs
+func (mw *metricWriter) sample(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteString("{")
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(labelEscaper.Replace(labels[i+1]))
+			b.WriteString(`"`)
+		}
+		b.WriteString("}")
+	}
+	mw.printf("%s %s\n", b.String(), formatFloat(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,45 @@
+package restuss
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientMetricsRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	m := NewClientMetrics()
+	c.SetMetrics(m)
+
+	err = c.LaunchScan(42)
+	if err == nil {
+		t.Fatalf("got no error, expected the retry limit exceeded")
+	}
+
+	var buf bytes.Buffer
+	_, err = m.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The last attempt isn't tried again, but it was rate limited.
+	for _, want := range []string{
+		`restuss_client_requests_total{method="POST",code="429"} 10`,
+		"restuss_client_retries_total 9",
+		"restuss_client_rate_limited_total 10",
+	} {
+		if !strings.Contains(buf.String(), want+"\n") {
+			t.Fatalf("got metrics:\n%s\nexpected: %s", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// DefaultNetworkID is the UUID of the default network, the one of the
+// scanners and exclusions not assigned to any other network.
+const DefaultNetworkID = "00000000-0000-0000-0000-000000000000"
+
+// ListNetworks returns all the networks
+func (c *NessusClient) ListNetworks() ([]Network, error) {
+	return c.ListNetworksContext(context.Background())
+}
+
+// ListNetworksContext returns all the networks using the given context.
+func (c *NessusClient) ListNetworksContext(ctx context.Context) ([]Network, error) {
+	var networks []Network
+	err := c.getAllPages(ctx, "/networks", nil, "networks", func(raw json.RawMessage) (int, error) {
+		var page []Network
+		err := json.Unmarshal(raw, &page)
+		networks = append(networks, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
+// GetNetwork retrieves a network by UUID
+func (c *NessusClient) GetNetwork(uuid string) (*Network, error) {
+	return c.GetNetworkContext(context.Background(), uuid)
+}
+
+// GetNetworkContext retrieves a network by UUID using the given context.
+func (c *NessusClient) GetNetworkContext(ctx context.Context, uuid string) (*Network, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/networks/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Network{}
+	err = c.performCallAndReadResponse(req, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// CreateNetwork creates a network
+func (c *NessusClient) CreateNetwork(network *Network) (*Network, error) {
+	return c.CreateNetworkContext(context.Background(), network)
+}
+
+// CreateNetworkContext creates a network using the given context.
+func (c *NessusClient) CreateNetworkContext(ctx context.Context, network *Network) (*Network, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/networks", networkPayload(network))
+	if err != nil {
+		return nil, err
+	}
+
+	created := &Network{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// EditNetwork updates the network with the UUID of the given network
+func (c *NessusClient) EditNetwork(network *Network) (*Network, error) {
+	return c.EditNetworkContext(context.Background(), network)
+}
+
+// EditNetworkContext updates the network with the UUID of the given network using the given context.
+func (c *NessusClient) EditNetworkContext(ctx context.Context, network *Network) (*Network, error) {
+	path := "/networks/" + url.PathEscape(network.UUID)
+	req, err := c.newRequest(ctx, http.MethodPut, path, networkPayload(network))
+	if err != nil {
+		return nil, err
+	}
+
+	edited := &Network{}
+	err = c.performCallAndReadResponse(req, edited)
+	if err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+// DeleteNetwork removes the network with the given UUID, its scanners are
+// moved back to the default network.
+func (c *NessusClient) DeleteNetwork(uuid string) error {
+	return c.DeleteNetworkContext(context.Background(), uuid)
+}
+
+// DeleteNetworkContext removes the network with the given UUID using the given context.
+func (c *NessusClient) DeleteNetworkContext(ctx context.Context, uuid string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/networks/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ListNetworkScanners returns the scanners assigned to a network
+func (c *NessusClient) ListNetworkScanners(networkUUID string) ([]Scanner, error) {
+	return c.ListNetworkScannersContext(context.Background(), networkUUID)
+}
+
+// ListNetworkScannersContext returns the scanners assigned to a network using the given context.
+func (c *NessusClient) ListNetworkScannersContext(ctx context.Context, networkUUID string) ([]Scanner, error) {
+	return c.getScanners(ctx, "/networks/"+url.PathEscape(networkUUID)+"/scanners")
+}
+
+// AssignScannersToNetwork moves the scanners with the given UUIDs to a network
+func (c *NessusClient) AssignScannersToNetwork(networkUUID string, scannerUUIDs []string) error {
+	return c.AssignScannersToNetworkContext(context.Background(), networkUUID, scannerUUIDs)
+}
+
+// AssignScannersToNetworkContext moves the scanners with the given UUIDs to a
+// network using the given context.
+func (c *NessusClient) AssignScannersToNetworkContext(ctx context.Context, networkUUID string, scannerUUIDs []string) error {
+	payload := map[string][]string{"scanner_uuids": scannerUUIDs}
+	path := "/networks/" + url.PathEscape(networkUUID) + "/scanners"
+	req, err := c.newRequest(ctx, http.MethodPost, path, payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+func networkPayload(n *Network) map[string]interface{} {
+	payload := map[string]interface{}{
+		"name":        n.Name,
+		"description": n.Description,
+	}
+	if n.AssetsTTLDays > 0 {
+		payload["assets_ttl_days"] = n.AssetsTTLDays
+	}
+	return payload
+}
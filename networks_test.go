@@ -0,0 +1,109 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newSearchServer returns a server answering the searches of the API v3 with
+// response, the filters received being appended to filters
+func newSearchServer(t *testing.T, path, response string, filters *[]interface{}) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != path {
+				t.Errorf("got request: %s %s, expected: POST %s", r.Method, r.URL.Path, path)
+			}
+			var body struct {
+				Filter struct {
+					And []interface{} `json:"and"`
+				} `json:"filter"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*filters = append(*filters, body.Filter.And)
+			_, _ = w.Write([]byte(response))
+		}))
+}
+
+func TestGetAssetByNameInNetwork(t *testing.T) {
+	name := map[string]interface{}{"property": "name", "operator": "eq", "value": "web"}
+	network := map[string]interface{}{"property": "network.id", "operator": "eq", "value": "n-1"}
+	tests := []struct {
+		name      string
+		networkID string
+		want      []interface{}
+	}{
+		{name: "all networks", want: []interface{}{name}},
+		{name: "network", networkID: "n-1", want: []interface{}{name, network}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var filters []interface{}
+			ts := newSearchServer(t, "/api/v3/assets/search", `{"assets":[{"id":"a-1","name":"web"}]}`, &filters)
+			defer ts.Close()
+
+			c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+			if err != nil {
+				t.Fatalf("Error creating new client: %v", err)
+			}
+
+			asset, err := c.GetAssetByNameInNetwork(context.Background(), "web", tc.networkID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if asset.ID != "a-1" {
+				t.Fatalf("got asset: %s, expected: a-1", asset.ID)
+			}
+			if len(filters) != 1 || !reflect.DeepEqual(filters[0], tc.want) {
+				t.Fatalf("got filters: %v, expected: %v", filters, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAssetByNameInNetworkAmbiguous(t *testing.T) {
+	var filters []interface{}
+	ts := newSearchServer(t, "/api/v3/assets/search", `{"assets":[{"id":"a-1"},{"id":"a-2"}]}`, &filters)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	if _, err := c.GetAssetByName(context.Background(), "web"); err == nil {
+		t.Fatalf("got no error, expected more than one asset matching")
+	}
+}
+
+func TestGetFindingsByAssetNameInNetwork(t *testing.T) {
+	var filters []interface{}
+	ts := newSearchServer(t, "/api/v3/findings/vulnerabilities/host/search",
+		`{"findings":[{"id":"f-1"}],"pagination":{"total":1}}`, &filters)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	findings, err := c.GetFindingsByAssetNameInNetwork(context.Background(), "web", "n-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("got: %d findings, expected: 1", len(findings))
+	}
+	want := []interface{}{
+		map[string]interface{}{"property": "asset.name", "operator": "eq", "value": "web"},
+		map[string]interface{}{"property": "asset.network.id", "operator": "eq", "value": "n-1"},
+	}
+	if len(filters) != 1 || !reflect.DeepEqual(filters[0], want) {
+		t.Fatalf("got filters: %v, expected: %v", filters, want)
+	}
+}
@@ -0,0 +1,406 @@
+package restuss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// ScanJob represents a scan to be created, launched and waited for by an Orchestrator
+type ScanJob struct {
+	// ID identifies the job, it is used to re-attach to the job after a restart.
+	ID           string       `json:"id"`
+	TemplateUUID string       `json:"template_uuid"`
+	Targets      string       `json:"targets"`
+	Settings     ScanSettings `json:"settings"`
+	// ExportFormat, when not empty, exports the finished scan in the given
+	// format ("nessus", "csv", "html", "pdf").
+	ExportFormat string `json:"export_format,omitempty"`
+	// Delete removes the scan once finished and exported.
+	Delete bool `json:"delete,omitempty"`
+}
+
+// ScanJobResult represents the outcome of a ScanJob
+type ScanJobResult struct {
+	Job    ScanJob
+	Scan   *PersistedScan
+	Detail *ScanDetail
+	Export []byte
+	Err    error
+}
+
+// ScanFuture gives access to the result of a ScanJob once finished
+type ScanFuture struct {
+	done   chan struct{}
+	result ScanJobResult
+}
+
+// Done returns a channel closed when the job is finished
+func (f *ScanFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the job is finished or ctx is done
+func (f *ScanFuture) Wait(ctx context.Context) (*ScanJobResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+		return &f.result, f.result.Err
+	}
+}
+
+// ScanJobRecord represents the persisted state of an in-flight job
+type ScanJobRecord struct {
+	Job    ScanJob `json:"job"`
+	ScanID int64   `json:"scan_id"`
+	// ScanUUID is the UUID of the scan when created, then the one of the run
+	// once launched.
+	ScanUUID string `json:"scan_uuid"`
+	Launched bool   `json:"launched"`
+}
+
+// JobStore persists in-flight jobs so an Orchestrator can re-attach to them
+// after a restart.
+type JobStore interface {
+	SaveJob(ctx context.Context, record *ScanJobRecord) error
+	DeleteJob(ctx context.Context, jobID string) error
+	ListJobs(ctx context.Context) ([]*ScanJobRecord, error)
+}
+
+// OrchestratorOptions configures an Orchestrator
+type OrchestratorOptions struct {
+	// MaxRunningPerScanner defaults to 1.
+	MaxRunningPerScanner int
+	// PollInterval between two scan status checks, defaults to 30 seconds.
+	PollInterval time.Duration
+	// Retries is the number of times a step failing with a temporary error
+	// is tried again, defaults to 3.
+	Retries int
+	// Store persists in-flight jobs, jobs are only kept in memory when nil.
+	Store JobStore
+}
+
+// Orchestrator queues scan jobs per scanner and runs at most
+// MaxRunningPerScanner of them at once on each scanner.
+type Orchestrator struct {
+	client *NessusClient
+	opts   OrchestratorOptions
+
+	mu      sync.Mutex
+	queues  map[string][]*queuedJob
+	running map[string]int
+	futures map[string]*ScanFuture
+}
+
+type queuedJob struct {
+	ctx    context.Context
+	record *ScanJobRecord
+	future *ScanFuture
+}
+
+// NewOrchestrator returns a new Orchestrator
+func NewOrchestrator(client *NessusClient, opts OrchestratorOptions) *Orchestrator {
+	if opts.MaxRunningPerScanner <= 0 {
+		opts.MaxRunningPerScanner = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	return &Orchestrator{
+		client:  client,
+		opts:    opts,
+		queues:  map[string][]*queuedJob{},
+		running: map[string]int{},
+		futures: map[string]*ScanFuture{},
+	}
+}
+
+// Submit queues a job. Cancelling ctx stops waiting for the job but leaves
+// the scan running and its record in the store.
+func (o *Orchestrator) Submit(ctx context.Context, job ScanJob) (*ScanFuture, error) {
+	if job.ID == "" {
+		return nil, errors.New("Job ID is required")
+	}
+	r := &ScanJobRecord{Job: job}
+	f, err := o.reserve(r)
+	if err != nil {
+		return nil, err
+	}
+	// The record is only saved once the job is accepted, so a duplicate
+	// doesn't overwrite the record of the job in flight.
+	o.save(ctx, r)
+	o.enqueue(ctx, r, f)
+	return f, nil
+}
+
+// Resume re-attaches to the in-flight jobs found in the store, matching them
+// with existing scans by ID, as the UUID of a scan changes with every launch.
+// It should be called once after a restart, before submitting new jobs.
+func (o *Orchestrator) Resume(ctx context.Context) ([]*ScanFuture, error) {
+	if o.opts.Store == nil {
+		return nil, nil
+	}
+	records, err := o.opts.Store.ListJobs(ctx)
+	if err != nil {
+		return nil, errors.New("Unable to list jobs: " + err.Error())
+	}
+
+	list, err := o.client.GetScanListContext(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[int64]bool{}
+	for _, s := range list.Scans {
+		byID[s.ID] = true
+	}
+
+	var futures []*ScanFuture
+	for _, r := range records {
+		if r.ScanID != 0 && !byID[r.ScanID] {
+			log.Printf("Scan %d of job %s not found, submitting it again", r.ScanID, r.Job.ID)
+			r = &ScanJobRecord{Job: r.Job}
+		}
+		f, err := o.reserve(r)
+		if err != nil {
+			return nil, err
+		}
+		o.enqueue(ctx, r, f)
+		futures = append(futures, f)
+	}
+
+	return futures, nil
+}
+
+// Future returns the future of an in-flight job
+func (o *Orchestrator) Future(jobID string) (*ScanFuture, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	f, ok := o.futures[jobID]
+	return f, ok
+}
+
+// reserve registers the future of a job, failing if the job is already in
+// flight
+func (o *Orchestrator) reserve(r *ScanJobRecord) (*ScanFuture, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.futures[r.Job.ID]; ok {
+		return nil, fmt.Errorf("Job %s already submitted", r.Job.ID)
+	}
+	f := &ScanFuture{done: make(chan struct{}), result: ScanJobResult{Job: r.Job}}
+	o.futures[r.Job.ID] = f
+	return f, nil
+}
+
+// enqueue queues a reserved job
+func (o *Orchestrator) enqueue(ctx context.Context, r *ScanJobRecord, f *ScanFuture) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	scanner := r.Job.Settings.ScannerID
+	o.queues[scanner] = append(o.queues[scanner], &queuedJob{ctx: ctx, record: r, future: f})
+	o.dispatch(scanner)
+}
+
+// dispatch starts queued jobs while the scanner has free slots. It must be
+// called with the lock held.
+func (o *Orchestrator) dispatch(scanner string) {
+	for o.running[scanner] < o.opts.MaxRunningPerScanner && len(o.queues[scanner]) > 0 {
+		j := o.queues[scanner][0]
+		o.queues[scanner] = o.queues[scanner][1:]
+		o.running[scanner]++
+		go o.process(scanner, j)
+	}
+}
+
+func (o *Orchestrator) process(scanner string, j *queuedJob) {
+	res := o.run(j.ctx, j.record)
+	res.Job = j.record.Job
+
+	// The record is kept to resume the job only when it may still succeed.
+	keep := res.Err != nil && (IsTemporary(res.Err) || j.ctx.Err() != nil)
+	if !keep && o.opts.Store != nil {
+		err := o.opts.Store.DeleteJob(j.ctx, j.record.Job.ID)
+		if err != nil {
+			log.Printf("Error when deleting job %s: %v", j.record.Job.ID, err)
+		}
+	}
+
+	o.mu.Lock()
+	o.running[scanner]--
+	delete(o.futures, j.record.Job.ID)
+	o.dispatch(scanner)
+	o.mu.Unlock()
+
+	j.future.result = res
+	close(j.future.done)
+}
+
+func (o *Orchestrator) run(ctx context.Context, r *ScanJobRecord) ScanJobResult {
+	var res ScanJobResult
+
+	if r.ScanID == 0 {
+		scan := &Scan{TemplateUUID: r.Job.TemplateUUID, Settings: r.Job.Settings}
+		if r.Job.Targets != "" {
+			scan.Settings.Targets = r.Job.Targets
+		}
+		var persisted *PersistedScan
+		err := o.retry(ctx, func() error {
+			var err error
+			persisted, err = o.client.CreateScanContext(ctx, scan)
+			return err
+		})
+		if err != nil {
+			res.Err = fmt.Errorf("Unable to create scan: %w", err)
+			return res
+		}
+		r.ScanID, r.ScanUUID = persisted.ID, persisted.UUID
+		o.save(ctx, r)
+	}
+	res.Scan = &PersistedScan{ID: r.ScanID, UUID: r.ScanUUID}
+
+	if !r.Launched {
+		var uuid string
+		err := o.retry(ctx, func() error {
+			var err error
+			uuid, err = o.client.LaunchScanWithTargetsContext(ctx, r.ScanID, nil)
+			return err
+		})
+		if err != nil {
+			res.Err = fmt.Errorf("Unable to launch scan: %w", err)
+			return res
+		}
+		r.ScanUUID, r.Launched = uuid, true
+		res.Scan.UUID = uuid
+		o.save(ctx, r)
+	}
+
+	detail, err := o.wait(ctx, r.ScanID)
+	res.Detail = detail
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if r.Job.ExportFormat != "" {
+		res.Export, err = o.export(ctx, r.ScanID, r.Job.ExportFormat)
+		if err != nil {
+			res.Err = fmt.Errorf("Unable to export scan: %w", err)
+			return res
+		}
+	}
+
+	if r.Job.Delete {
+		err = o.retry(ctx, func() error {
+			return o.client.DeleteScanContext(ctx, r.ScanID)
+		})
+		if err != nil {
+			res.Err = fmt.Errorf("Unable to delete scan: %w", err)
+			return res
+		}
+	}
+
+	return res
+}
+
+func (o *Orchestrator) wait(ctx context.Context, scanID int64) (*ScanDetail, error) {
+	for {
+		var detail *ScanDetail
+		err := o.retry(ctx, func() error {
+			var err error
+			detail, err = o.client.GetScanByIDContext(ctx, scanID)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get scan: %w", err)
+		}
+
+		switch detail.Info.Status {
+		case "completed", "imported":
+			return detail, nil
+		case "canceled", "aborted":
+			return detail, &ScanFailedError{ScanID: scanID, Status: detail.Info.Status}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(o.opts.PollInterval):
+		}
+	}
+}
+
+func (o *Orchestrator) export(ctx context.Context, scanID int64, format string) ([]byte, error) {
+	var fileID int64
+	err := o.retry(ctx, func() error {
+		var err error
+		fileID, err = o.client.ExportScanContext(ctx, scanID, &ScanExport{Format: format})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.retry(ctx, func() error {
+		return o.client.WaitScanExportContext(ctx, scanID, fileID, o.opts.PollInterval)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = o.retry(ctx, func() error {
+		buf.Reset()
+		return o.client.DownloadScanExportContext(ctx, scanID, fileID, &buf)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// retry calls fn again while it fails with a temporary error, errors caused
+// by the request itself are returned straight away.
+func (o *Orchestrator) retry(ctx context.Context, fn func() error) error {
+	b := &backoff.Backoff{
+		Min:    time.Second,
+		Max:    5 * time.Minute,
+		Factor: 2,
+		Jitter: true,
+	}
+	var err error
+	for i := 0; i <= o.opts.Retries; i++ {
+		err = fn()
+		if err == nil || !IsTemporary(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(b.Duration()):
+		}
+	}
+	return err
+}
+
+func (o *Orchestrator) save(ctx context.Context, r *ScanJobRecord) {
+	if o.opts.Store == nil {
+		return
+	}
+	err := o.opts.Store.SaveJob(ctx, r)
+	if err != nil {
+		log.Printf("Error when saving job %s: %v", r.Job.ID, err)
+	}
+}
@@ -0,0 +1,154 @@
+package restuss_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adevinta/restuss"
+	"github.com/adevinta/restuss/restusstest"
+)
+
+// jobStore is a JobStore keeping the records in memory
+type jobStore struct {
+	mu      sync.Mutex
+	records map[string]restuss.ScanJobRecord
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{records: map[string]restuss.ScanJobRecord{}}
+}
+
+func (s *jobStore) SaveJob(ctx context.Context, r *restuss.ScanJobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[r.Job.ID] = *r
+	return nil
+}
+
+func (s *jobStore) DeleteJob(ctx context.Context, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, jobID)
+	return nil
+}
+
+func (s *jobStore) ListJobs(ctx context.Context) ([]*restuss.ScanJobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []*restuss.ScanJobRecord
+	for _, r := range s.records {
+		r := r
+		records = append(records, &r)
+	}
+	return records, nil
+}
+
+func (s *jobStore) get(jobID string) (restuss.ScanJobRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[jobID]
+	return r, ok
+}
+
+// launchJob submits a job and waits for its scan to be launched, returning
+// the record of the job and a function cancelling the submission
+func launchJob(t *testing.T, o *restuss.Orchestrator, store *jobStore, jobID string) (restuss.ScanJobRecord, *restuss.ScanFuture, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	f, err := o.Submit(ctx, restuss.ScanJob{
+		ID:           jobID,
+		TemplateUUID: restusstest.TemplateBasic,
+		Targets:      "a.example.com",
+		Settings:     restuss.ScanSettings{Name: jobID},
+	})
+	if err != nil {
+		cancel()
+		t.Fatalf("Error submitting job: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if r, ok := store.get(jobID); ok && r.Launched {
+			return r, f, cancel
+		}
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatalf("timed out waiting for the job to be launched")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOrchestratorResumeLaunched(t *testing.T) {
+	s := restusstest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	store := newJobStore()
+
+	o := restuss.NewOrchestrator(c, restuss.OrchestratorOptions{PollInterval: 5 * time.Millisecond, Store: store})
+	launched, f, cancel := launchJob(t, o, store, "job-1")
+	// The scan keeps running while nothing waits for it, as after a restart.
+	cancel()
+	<-f.Done()
+	if _, ok := store.get("job-1"); !ok {
+		t.Fatalf("got the record of the job deleted, expected it kept to resume it")
+	}
+
+	o = restuss.NewOrchestrator(c, restuss.OrchestratorOptions{PollInterval: 5 * time.Millisecond, Store: store})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	futures, err := o.Resume(ctx)
+	if err != nil {
+		t.Fatalf("Error resuming jobs: %v", err)
+	}
+	if len(futures) != 1 {
+		t.Fatalf("got %d jobs resumed, expected: 1", len(futures))
+	}
+	s.Clock.Advance(time.Hour)
+	res, err := futures[0].Wait(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Scan.ID != launched.ScanID || res.Detail.Info.Status != "completed" {
+		t.Fatalf("got scan %d with status %s, expected scan %d completed",
+			res.Scan.ID, res.Detail.Info.Status, launched.ScanID)
+	}
+
+	list, err := c.GetScanListContext(ctx, 0)
+	if err != nil {
+		t.Fatalf("Error listing scans: %v", err)
+	}
+	if len(list.Scans) != 1 {
+		t.Fatalf("got %d scans, expected the resumed scan only", len(list.Scans))
+	}
+	if _, ok := store.get("job-1"); ok {
+		t.Fatalf("got the record of the finished job kept")
+	}
+}
+
+func TestOrchestratorDeletesFailedJobs(t *testing.T) {
+	s := restusstest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	store := newJobStore()
+
+	o := restuss.NewOrchestrator(c, restuss.OrchestratorOptions{PollInterval: 5 * time.Millisecond, Store: store})
+	launched, f, cancel := launchJob(t, o, store, "job-1")
+	defer cancel()
+	err := c.StopScan(launched.ScanID)
+	if err != nil {
+		t.Fatalf("Error stopping scan: %v", err)
+	}
+
+	ctx, cancelWait := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelWait()
+	_, err = f.Wait(ctx)
+	if err == nil || ctx.Err() != nil {
+		t.Fatalf("got error: %v, expected the scan canceled", err)
+	}
+	if _, ok := store.get("job-1"); ok {
+		t.Fatalf("got the record of the failed job kept, expected it deleted")
+	}
+}
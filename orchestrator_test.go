@@ -0,0 +1,170 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrchestratorConcurrencyPerScanner(t *testing.T) {
+	var mu sync.Mutex
+	var nextID, running, maxRunning int64
+	polls := map[string]int{}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/scans":
+				nextID++
+				_ = json.NewEncoder(w).Encode(map[string]PersistedScan{
+					"scan": {ID: nextID, UUID: fmt.Sprintf("uuid-%d", nextID)},
+				})
+			case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/launch"):
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				_, _ = w.Write([]byte(`{"scan_uuid":"run"}`))
+			case r.Method == http.MethodGet:
+				status := "running"
+				if polls[r.URL.Path]++; polls[r.URL.Path] > 1 {
+					status = "completed"
+					running--
+				}
+				_ = json.NewEncoder(w).Encode(ScanDetail{Info: Info{Status: status}})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	o := NewOrchestrator(c, OrchestratorOptions{
+		MaxRunningPerScanner: 1,
+		PollInterval:         10 * time.Millisecond,
+	})
+
+	var futures []*ScanFuture
+	for i := 0; i < 3; i++ {
+		f, err := o.Submit(context.Background(), ScanJob{
+			ID:       fmt.Sprintf("job-%d", i),
+			Targets:  "127.0.0.1",
+			Settings: ScanSettings{ScannerID: "scanner"},
+		})
+		if err != nil {
+			t.Fatalf("Error submitting job: %v", err)
+		}
+		futures = append(futures, f)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, f := range futures {
+		res, err := f.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res.Detail.Info.Status != "completed" {
+			t.Fatalf("got status: %v, expected: completed", res.Detail.Info.Status)
+		}
+	}
+
+	if maxRunning != 1 {
+		t.Fatalf("got %d scans running at once, expected: 1", maxRunning)
+	}
+}
+
+// memoryJobStore is a JobStore keeping the records in memory
+type memoryJobStore struct {
+	mu      sync.Mutex
+	records map[string]ScanJobRecord
+}
+
+func (s *memoryJobStore) SaveJob(ctx context.Context, r *ScanJobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[r.Job.ID] = *r
+	return nil
+}
+
+func (s *memoryJobStore) DeleteJob(ctx context.Context, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, jobID)
+	return nil
+}
+
+func (s *memoryJobStore) ListJobs(ctx context.Context) ([]*ScanJobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []*ScanJobRecord
+	for _, r := range s.records {
+		r := r
+		records = append(records, &r)
+	}
+	return records, nil
+}
+
+func (s *memoryJobStore) get(jobID string) ScanJobRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records[jobID]
+}
+
+func TestOrchestratorSubmitDuplicate(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/scans":
+				_ = json.NewEncoder(w).Encode(map[string]PersistedScan{"scan": {ID: 7, UUID: "uuid-7"}})
+			case r.Method == http.MethodPost && r.URL.Path == "/scans/7/launch":
+				_, _ = w.Write([]byte(`{"scan_uuid":"run-7"}`))
+			case r.Method == http.MethodGet:
+				_ = json.NewEncoder(w).Encode(ScanDetail{Info: Info{Status: "running"}})
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	store := &memoryJobStore{records: map[string]ScanJobRecord{}}
+	o := NewOrchestrator(c, OrchestratorOptions{PollInterval: 10 * time.Millisecond, Store: store})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	job := ScanJob{ID: "job-1", Targets: "127.0.0.1"}
+	_, err = o.Submit(ctx, job)
+	if err != nil {
+		t.Fatalf("Error submitting job: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !store.get("job-1").Launched {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the job to be launched")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_, err = o.Submit(ctx, job)
+	if err == nil {
+		t.Fatalf("got no error submitting a job in flight")
+	}
+	r := store.get("job-1")
+	if r.ScanID != 7 || r.ScanUUID != "run-7" || !r.Launched {
+		t.Fatalf("got record %+v after a duplicate submission, expected the launched scan", r)
+	}
+}
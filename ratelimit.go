@@ -0,0 +1,203 @@
+package restuss
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the budget of requests of a kind
+type RateLimit struct {
+	// Rate is the number of requests per second, no limit being applied
+	// when zero
+	Rate float64
+	// Burst is the number of requests which can be made at once, one when
+	// lower
+	Burst int
+}
+
+// RateLimiter spaces out the requests made by clients so they stay within
+// the rate limits of the API, instead of waiting for it to reject them. Calls
+// to the export endpoints, those whose name as returned by EndpointName
+// contains "export", have their own budget.
+//
+// The limiter adapts to the API: it halves the rate of a budget when a
+// request is rejected with status code 429, waiting for the time in its
+// Retry-After header, and gradually restores it as requests succeed. It also
+// follows the X-RateLimit-Remaining and X-RateLimit-Reset headers when the
+// API sends them.
+//
+// It is safe for concurrent use, and clients sharing API keys should share
+// a limiter.
+type RateLimiter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	regular *tokenBucket
+	export  *tokenBucket
+}
+
+// NewRateLimiter returns a RateLimiter with the given budgets
+func NewRateLimiter(regular, export RateLimit) *RateLimiter {
+	return &RateLimiter{
+		now:     time.Now,
+		regular: newTokenBucket(regular),
+		export:  newTokenBucket(export),
+	}
+}
+
+// SetRateLimiter makes the client wait for l before each request, nil
+// disabling it
+func (c *NessusClient) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// Wait blocks until a request to the endpoint, named as by EndpointName, can
+// be made or the context is done
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	l.mu.Lock()
+	d := l.bucket(endpoint).reserve(l.now())
+	l.mu.Unlock()
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Observe adapts the budget of the endpoint to a response of the API
+func (l *RateLimiter) Observe(endpoint string, res *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(endpoint)
+	b.refill(now)
+	if res.StatusCode == http.StatusTooManyRequests {
+		b.throttle()
+		if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			b.pause(now, now.Add(time.Duration(s)*time.Second))
+		}
+	} else if res.StatusCode < 300 {
+		b.restore()
+	}
+
+	remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	b.tokens = math.Min(b.tokens, float64(remaining))
+	if remaining > 0 {
+		return
+	}
+	reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	// The reset is either a timestamp or a number of seconds.
+	if reset > 1e9 {
+		b.pause(now, time.Unix(reset, 0))
+	} else {
+		b.pause(now, now.Add(time.Duration(reset)*time.Second))
+	}
+}
+
+func (l *RateLimiter) bucket(endpoint string) *tokenBucket {
+	if strings.Contains(endpoint, "export") {
+		return l.export
+	}
+	return l.regular
+}
+
+// wait is Wait for clients without a limiter too
+func (l *RateLimiter) wait(ctx context.Context, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+	return l.Wait(ctx, endpoint)
+}
+
+// observe is Observe for clients without a limiter too
+func (l *RateLimiter) observe(endpoint string, res *http.Response) {
+	if l == nil {
+		return
+	}
+	l.Observe(endpoint, res)
+}
+
+// tokenBucket is a token bucket whose tokens go negative as requests are
+// reserved ahead of time
+type tokenBucket struct {
+	limit  RateLimit
+	rate   float64
+	tokens float64
+	// last is when tokens was last refilled, in the future while paused
+	last time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, rate: limit.Rate, tokens: float64(limit.Burst)}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.limit.Rate <= 0 {
+		return
+	}
+	if b.last.IsZero() {
+		b.last = now
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.limit.Rate <= 0 {
+		return 0
+	}
+	b.refill(now)
+	b.tokens--
+
+	wait := b.last.Sub(now)
+	if b.tokens < 0 {
+		wait += time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	return wait
+}
+
+// pause stops refilling the bucket until the given time
+func (b *tokenBucket) pause(now, until time.Time) {
+	if b.limit.Rate <= 0 {
+		return
+	}
+	b.refill(now)
+	if until.After(b.last) {
+		b.tokens = math.Min(b.tokens, 0)
+		b.last = until
+	}
+}
+
+// throttle halves the rate, down to a sixteenth of the configured one
+func (b *tokenBucket) throttle() {
+	b.rate = math.Max(b.rate/2, b.limit.Rate/16)
+}
+
+// restore raises the rate back towards the configured one
+func (b *tokenBucket) restore() {
+	b.rate = math.Min(b.rate+b.limit.Rate/20, b.limit.Rate)
+}
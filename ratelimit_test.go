@@ -0,0 +1,117 @@
+package restuss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewRateLimiter(RateLimit{Rate: 2, Burst: 2}, RateLimit{Rate: 1})
+	l.now = func() time.Time { return now }
+	reserve := func(endpoint string) time.Duration {
+		return l.bucket(endpoint).reserve(l.now())
+	}
+
+	// The burst is spent straight away, the next requests are spaced out.
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if d := reserve("scans.list"); d != expected {
+			t.Fatalf("got wait %v for request %d, expected %v", d, i, expected)
+		}
+	}
+	// Exports have their own budget.
+	if d := reserve("scans.export_request"); d != 0 {
+		t.Fatalf("got wait %v for export, expected none", d)
+	}
+
+	now = now.Add(10 * time.Second)
+	res := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	res.Header.Set("Retry-After", "3")
+	l.Observe("scans.details", res)
+	if d := reserve("scans.details"); d != 4*time.Second {
+		t.Fatalf("got wait %v after rate limiting, expected 4s at half the rate", d)
+	}
+
+	now = now.Add(time.Minute)
+	res = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	res.Header.Set("X-RateLimit-Remaining", "0")
+	res.Header.Set("X-RateLimit-Reset", "5")
+	l.Observe("scans.details", res)
+	if d := reserve("scans.details"); d <= 5*time.Second {
+		t.Fatalf("got wait %v for an exhausted limit, expected more than 5s", d)
+	}
+	if l.regular.rate <= 1 || l.regular.rate > 2 {
+		t.Fatalf("got rate %v after a success, expected it to recover", l.regular.rate)
+	}
+}
+
+func TestRateLimiterConcurrentWait(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Rate: 100, Burst: 1}, RateLimit{})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- l.Wait(context.Background(), "scans.list")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	// Each request after the first waits for its own 10ms slot.
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Fatalf("got 5 requests in %v, expected at least 40ms at 100 requests per second", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = NewRateLimiter(RateLimit{Rate: 0.001}, RateLimit{})
+	_ = l.Wait(ctx, "scans.list")
+	if err := l.Wait(ctx, "scans.list"); err != context.Canceled {
+		t.Fatalf("got error: %v, expected: %v", err, context.Canceled)
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		times = append(times, time.Now())
+		_, _ = w.Write([]byte(`{"scans":[]}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	c.SetRateLimiter(NewRateLimiter(RateLimit{Rate: 20, Burst: 1}, RateLimit{}))
+
+	for i := 0; i < 3; i++ {
+		_, err = c.GetScanList(0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(times) != 3 {
+		t.Fatalf("got %d requests, expected: 3", len(times))
+	}
+	if d := times[2].Sub(times[0]); d < 90*time.Millisecond {
+		t.Fatalf("got 3 requests in %v, expected at least 100ms at 20 requests per second", d)
+	}
+}
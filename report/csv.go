@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CSVWriter writes records as CSV, with a header line
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []string
+	header  bool
+}
+
+// NewCSVWriter returns a CSVWriter writing the given columns to w, or
+// DefaultColumns when none are given
+func NewCSVWriter(w io.Writer, columns ...string) *CSVWriter {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	return &CSVWriter{w: csv.NewWriter(w), columns: columns}
+}
+
+// Write writes a record as a CSV line
+func (c *CSVWriter) Write(r Record) error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	line := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		if v := r.Value(col); v != nil {
+			line[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(line)
+}
+
+// Close writes the header if no record was written and flushes the output
+func (c *CSVWriter) Close() error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *CSVWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	return c.w.Write(c.columns)
+}
+
+// JSONLWriter writes records as JSON Lines, one object per record
+type JSONLWriter struct {
+	w       *bufio.Writer
+	enc     *json.Encoder
+	columns []string
+}
+
+// NewJSONLWriter returns a JSONLWriter writing the given columns to w, or
+// DefaultColumns when none are given
+func NewJSONLWriter(w io.Writer, columns ...string) *JSONLWriter {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	bw := bufio.NewWriter(w)
+	return &JSONLWriter{w: bw, enc: json.NewEncoder(bw), columns: columns}
+}
+
+// Write writes a record as a JSON line
+func (j *JSONLWriter) Write(r Record) error {
+	obj := make(map[string]interface{}, len(j.columns))
+	for _, col := range j.columns {
+		obj[col] = r.Value(col)
+	}
+	return j.enc.Encode(obj)
+}
+
+// Close flushes the output
+func (j *JSONLWriter) Close() error {
+	return j.w.Flush()
+}
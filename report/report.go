@@ -0,0 +1,239 @@
+// Package report renders findings, assets and scan vulnerabilities as CSV,
+// JSON Lines, and Markdown or HTML summaries.
+//
+// Rows are written one at a time to a Writer, which never keeps them around:
+// CSV and JSON Lines writers output each row as it comes and summaries only
+// keep counts.
+//
+//	w := report.NewCSVWriter(os.Stdout, "asset", "plugin_id", "severity")
+//	err := report.WriteAssetFindings(ctx, client, w, assets, nil)
+package report
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/adevinta/restuss"
+)
+
+// Severity names, indexed by Nessus severity
+var severityNames = []string{"Info", "Low", "Medium", "High", "Critical"}
+
+// SeverityName returns the name of a Nessus severity
+func SeverityName(severity int) string {
+	if severity < 0 || severity >= len(severityNames) {
+		return strconv.Itoa(severity)
+	}
+	return severityNames[severity]
+}
+
+// Record is a row of a report: a finding, a vulnerability of a scan or an asset
+type Record struct {
+	Asset      string
+	PluginID   int64
+	PluginName string
+	Family     string
+	Severity   int
+	Port       int
+	Protocol   string
+	Service    string
+	State      string
+	CVSS3      *float32
+	CVE        []string
+	// Count is the number of occurrences the record stands for, 1 for findings
+	Count  int64
+	Output string
+	// Extra holds columns specific to the kind of record
+	Extra map[string]string
+}
+
+// Columns of a Record, extra columns are selected by their name in Record.Extra
+const (
+	ColumnAsset      = "asset"
+	ColumnPluginID   = "plugin_id"
+	ColumnPluginName = "plugin_name"
+	ColumnFamily     = "family"
+	ColumnSeverity   = "severity"
+	ColumnPort       = "port"
+	ColumnProtocol   = "protocol"
+	ColumnService    = "service"
+	ColumnState      = "state"
+	ColumnCVSS3      = "cvss3"
+	ColumnCVE        = "cve"
+	ColumnCount      = "count"
+	ColumnOutput     = "output"
+)
+
+// DefaultColumns are the columns written when none are selected
+var DefaultColumns = []string{
+	ColumnAsset, ColumnPluginID, ColumnPluginName, ColumnSeverity,
+	ColumnPort, ColumnProtocol, ColumnState, ColumnCVSS3, ColumnCount,
+}
+
+// Value returns the value of a column of the record, with its JSON type
+func (r *Record) Value(column string) interface{} {
+	switch column {
+	case ColumnAsset:
+		return r.Asset
+	case ColumnPluginID:
+		return r.PluginID
+	case ColumnPluginName:
+		return r.PluginName
+	case ColumnFamily:
+		return r.Family
+	case ColumnSeverity:
+		return SeverityName(r.Severity)
+	case ColumnPort:
+		return r.Port
+	case ColumnProtocol:
+		return r.Protocol
+	case ColumnService:
+		return r.Service
+	case ColumnState:
+		return r.State
+	case ColumnCVSS3:
+		if r.CVSS3 == nil {
+			return nil
+		}
+		return *r.CVSS3
+	case ColumnCVE:
+		return strings.Join(r.CVE, " ")
+	case ColumnCount:
+		return r.Count
+	case ColumnOutput:
+		return r.Output
+	}
+	return r.Extra[column]
+}
+
+// Writer writes the records of a report. Close must be called once all the
+// records are written.
+type Writer interface {
+	Write(r Record) error
+	Close() error
+}
+
+// FromFinding returns the record of a finding of the given asset, which is
+// used when the finding doesn't carry its asset attributes
+func FromFinding(asset string, f restuss.Finding) Record {
+	switch {
+	case len(f.Asset.FQDN) > 0:
+		asset = f.Asset.FQDN[0]
+	case len(f.Asset.IPv4) > 0:
+		asset = f.Asset.IPv4[0]
+	case f.Asset.Name != "":
+		asset = f.Asset.Name
+	}
+	return Record{
+		Asset:      asset,
+		PluginID:   int64(f.Definition.ID),
+		PluginName: f.Definition.Name,
+		Severity:   f.Severity,
+		Port:       f.Port,
+		Protocol:   f.Protocol,
+		Service:    f.Service,
+		State:      f.State,
+		CVSS3:      f.Definition.CVSS3.BaseScore,
+		CVE:        f.Definition.CVE,
+		Count:      1,
+		Output:     f.Output,
+	}
+}
+
+// FromVulnerability returns the record of a vulnerability of a scan, its
+// asset is the scan name as scan details don't tell the hosts
+func FromVulnerability(scanName string, v restuss.Vulnerability) Record {
+	return Record{
+		Asset:      scanName,
+		PluginID:   v.PluginID,
+		PluginName: v.PluginName,
+		Family:     v.PluginFamily,
+		Severity:   int(v.Severity),
+		Count:      v.Count,
+	}
+}
+
+// FromAsset returns the record of a workbench asset. Its severity is the
+// highest one found on the asset, its count the number of vulnerabilities,
+// and the counts per severity are available as extra columns named after
+// the lower case severity ("critical", "high"...) together with "ipv4",
+// "fqdn", "operating_system" and "last_seen".
+func FromAsset(a restuss.WorkbenchAsset) Record {
+	r := Record{
+		Extra: map[string]string{
+			"ipv4":             strings.Join(a.IPv4, " "),
+			"fqdn":             strings.Join(a.FQDN, " "),
+			"operating_system": strings.Join(a.OperatingSystem, " "),
+			"last_seen":        a.LastSeen,
+		},
+	}
+	switch {
+	case len(a.FQDN) > 0:
+		r.Asset = a.FQDN[0]
+	case len(a.IPv4) > 0:
+		r.Asset = a.IPv4[0]
+	case len(a.IPv6) > 0:
+		r.Asset = a.IPv6[0]
+	default:
+		r.Asset = a.ID
+	}
+	for _, s := range a.Severities {
+		r.Extra[strings.ToLower(SeverityName(s.Level))] = strconv.FormatInt(s.Count, 10)
+		r.Count += s.Count
+		if s.Count > 0 && s.Level > r.Severity {
+			r.Severity = s.Level
+		}
+	}
+	return r
+}
+
+// WriteFindings writes the findings of the given asset
+func WriteFindings(w Writer, asset string, findings []restuss.Finding) error {
+	for _, f := range findings {
+		err := w.Write(FromFinding(asset, f))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteVulnerabilities writes the vulnerabilities of a scan
+func WriteVulnerabilities(w Writer, detail *restuss.ScanDetail) error {
+	for _, v := range detail.Vulnerabilities {
+		err := w.Write(FromVulnerability(detail.Info.Name, v))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteAssets writes workbench assets
+func WriteAssets(w Writer, assets []restuss.WorkbenchAsset) error {
+	for _, a := range assets {
+		err := w.Write(FromAsset(a))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteAssetFindings searches the findings of each asset in turn and writes
+// them, so only the findings of one asset are held in memory at a time.
+// opts can be nil.
+func WriteAssetFindings(ctx context.Context, c *restuss.NessusClient, w Writer, assets []string, opts *restuss.FindingsOptions) error {
+	for _, asset := range assets {
+		findings, err := c.SearchFindingsByAssetName(ctx, asset, opts)
+		if err != nil {
+			return err
+		}
+		err = WriteFindings(w, asset, findings)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adevinta/restuss"
+)
+
+func testFindings() []restuss.Finding {
+	findings := make([]restuss.Finding, 3)
+	findings[0].Severity = 4
+	findings[0].Port = 443
+	findings[0].Protocol = "TCP"
+	findings[0].Definition.ID = 100
+	findings[0].Definition.Name = "Bad TLS"
+	findings[1] = findings[0]
+	findings[1].Port = 8443
+	findings[2].Severity = 1
+	findings[2].Port = 22
+	findings[2].Protocol = "TCP"
+	findings[2].Definition.ID = 200
+	findings[2].Definition.Name = "SSH, banner"
+	return findings
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, ColumnAsset, ColumnPluginName, ColumnSeverity, ColumnPort)
+	err := WriteFindings(w, "host", testFindings())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "asset,plugin_name,severity,port\n" +
+		"host,Bad TLS,Critical,443\n" +
+		"host,Bad TLS,Critical,8443\n" +
+		"host,\"SSH, banner\",Low,22\n"
+	if buf.String() != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSummary(&buf, Markdown, GroupByPort)
+	err := WriteFindings(s, "host", testFindings())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = s.Close()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, line := range []string{
+		"| Critical | 2 |",
+		"| 100 | Bad TLS | Critical | 2 |",
+		"| 443/tcp | 1 | 0 | 0 | 0 | 0 |",
+		"| 22/tcp | 0 | 0 | 0 | 1 | 0 |",
+	} {
+		if !strings.Contains(out, line) {
+			t.Fatalf("summary lacks %q:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is the output format of a summary
+type Format int
+
+// Summary formats
+const (
+	Markdown Format = iota
+	HTML
+)
+
+// GroupBy selects how a summary groups the records
+type GroupBy int
+
+// Summary groupings
+const (
+	GroupByAsset GroupBy = iota
+	GroupByPlugin
+	GroupByPort
+)
+
+func (g GroupBy) title() string {
+	switch g {
+	case GroupByPlugin:
+		return "Plugin"
+	case GroupByPort:
+		return "Port"
+	default:
+		return "Asset"
+	}
+}
+
+// Summary renders severity totals, the top plugins and the counts per
+// severity of each group. It only keeps counts, and renders when closed.
+type Summary struct {
+	// Title is the title of the summary
+	Title string
+	// Top is the number of plugins listed, 10 by default
+	Top int
+
+	w       io.Writer
+	format  Format
+	groupBy GroupBy
+	totals  [5]int64
+	plugins map[int64]*pluginCount
+	groups  map[string]*[5]int64
+}
+
+type pluginCount struct {
+	id       int64
+	name     string
+	severity int
+	count    int64
+}
+
+// NewSummary returns a Summary rendering to w in the given format
+func NewSummary(w io.Writer, format Format, groupBy GroupBy) *Summary {
+	return &Summary{
+		Title:   "Findings summary",
+		Top:     10,
+		w:       w,
+		format:  format,
+		groupBy: groupBy,
+		plugins: map[int64]*pluginCount{},
+		groups:  map[string]*[5]int64{},
+	}
+}
+
+// Write counts a record
+func (s *Summary) Write(r Record) error {
+	sev := r.Severity
+	if sev < 0 || sev >= len(s.totals) {
+		sev = 0
+	}
+	s.totals[sev] += r.Count
+
+	p, ok := s.plugins[r.PluginID]
+	if !ok {
+		p = &pluginCount{id: r.PluginID, name: r.PluginName}
+		s.plugins[r.PluginID] = p
+	}
+	p.count += r.Count
+	if r.Severity > p.severity {
+		p.severity = r.Severity
+	}
+
+	var key string
+	switch s.groupBy {
+	case GroupByPlugin:
+		key = strconv.FormatInt(r.PluginID, 10) + " " + r.PluginName
+	case GroupByPort:
+		key = strconv.Itoa(r.Port)
+		if r.Protocol != "" {
+			key += "/" + strings.ToLower(r.Protocol)
+		}
+	default:
+		key = r.Asset
+	}
+	g, ok := s.groups[key]
+	if !ok {
+		g = &[5]int64{}
+		s.groups[key] = g
+	}
+	g[sev] += r.Count
+	return nil
+}
+
+// Close renders the summary
+func (s *Summary) Close() error {
+	// Highest severities first, then most occurrences
+	plugins := make([]*pluginCount, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		a, b := plugins[i], plugins[j]
+		if a.severity != b.severity {
+			return a.severity > b.severity
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return a.id < b.id
+	})
+	if s.Top > 0 && len(plugins) > s.Top {
+		plugins = plugins[:s.Top]
+	}
+
+	groups := make([]string, 0, len(s.groups))
+	for k := range s.groups {
+		groups = append(groups, k)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := s.groups[groups[i]], s.groups[groups[j]]
+		for sev := len(a) - 1; sev >= 0; sev-- {
+			if a[sev] != b[sev] {
+				return a[sev] > b[sev]
+			}
+		}
+		return groups[i] < groups[j]
+	})
+
+	t := newTable(s.w, s.format)
+	t.heading(1, s.Title)
+
+	t.heading(2, "Severity totals")
+	t.header("Severity", "Count")
+	for sev := len(s.totals) - 1; sev >= 0; sev-- {
+		t.row(SeverityName(sev), strconv.FormatInt(s.totals[sev], 10))
+	}
+	t.end()
+
+	t.heading(2, "Top plugins")
+	t.header("Plugin ID", "Name", "Severity", "Count")
+	for _, p := range plugins {
+		t.row(strconv.FormatInt(p.id, 10), p.name, SeverityName(p.severity), strconv.FormatInt(p.count, 10))
+	}
+	t.end()
+
+	t.heading(2, "By "+strings.ToLower(s.groupBy.title()))
+	t.header(s.groupBy.title(), "Critical", "High", "Medium", "Low", "Info")
+	for _, k := range groups {
+		g := s.groups[k]
+		t.row(k,
+			strconv.FormatInt(g[4], 10), strconv.FormatInt(g[3], 10), strconv.FormatInt(g[2], 10),
+			strconv.FormatInt(g[1], 10), strconv.FormatInt(g[0], 10))
+	}
+	t.end()
+
+	return t.err
+}
+
+// table writes headings and tables in Markdown or HTML, keeping the first
+// error
+type table struct {
+	w      io.Writer
+	format Format
+	err    error
+}
+
+func newTable(w io.Writer, format Format) *table {
+	return &table{w: w, format: format}
+}
+
+func (t *table) printf(format string, args ...interface{}) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, format, args...)
+}
+
+func (t *table) heading(level int, text string) {
+	if t.format == HTML {
+		t.printf("<h%d>%s</h%d>\n", level, html.EscapeString(text), level)
+		return
+	}
+	t.printf("%s %s\n\n", strings.Repeat("#", level), markdownEscape(text))
+}
+
+func (t *table) header(cells ...string) {
+	if t.format == HTML {
+		t.printf("<table>\n<tr>")
+		for _, c := range cells {
+			t.printf("<th>%s</th>", html.EscapeString(c))
+		}
+		t.printf("</tr>\n")
+		return
+	}
+	t.row(cells...)
+	t.printf("|%s\n", strings.Repeat(" --- |", len(cells)))
+}
+
+func (t *table) row(cells ...string) {
+	if t.format == HTML {
+		t.printf("<tr>")
+		for _, c := range cells {
+			t.printf("<td>%s</td>", html.EscapeString(c))
+		}
+		t.printf("</tr>\n")
+		return
+	}
+	t.printf("|")
+	for _, c := range cells {
+		t.printf(" %s |", markdownEscape(c))
+	}
+	t.printf("\n")
+}
+
+func (t *table) end() {
+	if t.format == HTML {
+		t.printf("</table>\n")
+		return
+	}
+	t.printf("\n")
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,555 @@
+package restusstest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+// Folders of the scans
+const (
+	folderTrash = 2
+	folderMain  = 3
+)
+
+// Scan statuses
+const (
+	statusEmpty     = "empty"
+	statusPending   = "pending"
+	statusRunning   = "running"
+	statusPaused    = "paused"
+	statusCompleted = "completed"
+	statusCanceled  = "canceled"
+)
+
+type scan struct {
+	restuss.PersistedScan
+	targets string
+	results []restuss.HostFinding
+	runs    []*run
+}
+
+// run is a launch of a scan, which reports the results the scan had when
+// launched
+type run struct {
+	historyID int64
+	uuid      string
+	status    string
+	created   time.Time
+	modified  time.Time
+	// until is when a pending or running run moves on to the next status
+	until time.Time
+	// remaining is the time left to run by a paused run
+	remaining time.Duration
+	targets   string
+	results   []restuss.HostFinding
+}
+
+type export struct {
+	scanID  int64
+	ready   time.Time
+	content []byte
+}
+
+// SetResults sets the findings reported by the scan with the given ID from
+// its next launch on. Runs launched against alternative targets only report
+// the findings of those targets.
+func (s *Server) SetResults(scanID int64, findings []restuss.HostFinding) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sc, ok := s.scans[scanID]; ok {
+		sc.results = append([]restuss.HostFinding(nil), findings...)
+	}
+}
+
+// ScanStatus returns the status of the scan with the given ID, empty when
+// there is none
+func (s *Server) ScanStatus(scanID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.scans[scanID]
+	if !ok {
+		return ""
+	}
+	return s.update(sc).Status
+}
+
+func (s *Server) serveScans(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listScans(w, r)
+		case http.MethodPost:
+			s.createScan(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	id, err := strconv.ParseInt(segments[0], 10, 64)
+	sc, ok := s.scans[id]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "The requested file was not found")
+		return
+	}
+	s.update(sc)
+
+	action := r.Method + " " + strings.Join(segments[1:], "/")
+	switch {
+	case action == "GET ":
+		s.scanDetail(w, r, sc)
+	case action == "DELETE ":
+		if sc.active() {
+			writeError(w, http.StatusConflict, "Scan is running")
+			return
+		}
+		delete(s.scans, sc.ID)
+	case action == "POST launch":
+		s.launchScan(w, r, sc)
+	case action == "POST stop":
+		s.transition(w, sc, statusCanceled, statusPending, statusRunning, statusPaused)
+	case action == "POST pause":
+		s.transition(w, sc, statusPaused, statusRunning)
+	case action == "POST resume":
+		s.transition(w, sc, statusRunning, statusPaused)
+	case action == "POST export":
+		s.exportScan(w, r, sc)
+	case r.Method == http.MethodGet && len(segments) == 4 && segments[1] == "export":
+		s.serveExport(w, sc, segments[2], segments[3])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) listScans(w http.ResponseWriter, r *http.Request) {
+	since, _ := strconv.ParseInt(r.URL.Query().Get("last_modification_date"), 10, 64)
+	ids := make([]int64, 0, len(s.scans))
+	for id := range s.scans {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	scans := []*restuss.PersistedScan{}
+	for _, id := range ids {
+		p := s.update(s.scans[id])
+		// The scans modified during the second of the timestamp are listed
+		// again, the clock being frozen they could be modified after it.
+		if p.LastModificationDate >= since {
+			scans = append(scans, &p)
+		}
+	}
+	writeJSON(w, restuss.ScanList{
+		Folders: []restuss.Folder{
+			{ID: folderTrash, Name: "Trash", Type: "trash"},
+			{ID: folderMain, Name: "My Scans", Type: "main", DefaultTag: 1},
+		},
+		Scans:     scans,
+		Timestamp: s.Clock.Now().Unix(),
+	})
+}
+
+func (s *Server) createScan(w http.ResponseWriter, r *http.Request) {
+	var req restuss.Scan
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	found := false
+	for _, t := range s.templates {
+		found = found || t.UUID == req.TemplateUUID
+	}
+	if !found {
+		writeError(w, http.StatusBadRequest, "Invalid 'uuid' field")
+		return
+	}
+	if _, ok := s.policies[req.Settings.PolicyID]; req.Settings.PolicyID != 0 && !ok {
+		writeError(w, http.StatusBadRequest, "Invalid 'policy_id' field")
+		return
+	}
+	if req.Settings.Name == "" || req.Settings.Targets == "" {
+		writeError(w, http.StatusBadRequest, "The name and targets of the scan are required")
+		return
+	}
+
+	now := s.Clock.Now().Unix()
+	id := s.newID()
+	sc := &scan{
+		PersistedScan: restuss.PersistedScan{
+			ID: id,
+			// The UUID changes to the one of the run once launched.
+			UUID:                 "template-" + fakeUUID(id),
+			Name:                 req.Settings.Name,
+			Status:               statusEmpty,
+			CreationDate:         now,
+			LastModificationDate: now,
+			Owner:                s.Username,
+			FolderID:             folderMain,
+		},
+		targets: req.Settings.Targets,
+	}
+	s.scans[sc.ID] = sc
+	writeJSON(w, map[string]interface{}{"scan": sc.PersistedScan})
+}
+
+func (s *Server) launchScan(w http.ResponseWriter, r *http.Request, sc *scan) {
+	var req struct {
+		AltTargets []string `json:"alt_targets"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if sc.active() {
+		writeError(w, http.StatusConflict, "Scan is already running")
+		return
+	}
+
+	now := s.Clock.Now()
+	rn := &run{
+		historyID: s.newID(),
+		status:    statusPending,
+		created:   now,
+		modified:  now,
+		until:     now.Add(s.PendingDuration),
+		targets:   sc.targets,
+		results:   sc.results,
+	}
+	rn.uuid = fakeUUID(rn.historyID)
+	if len(req.AltTargets) > 0 {
+		rn.targets = strings.Join(req.AltTargets, ",")
+		rn.results = nil
+		for _, f := range sc.results {
+			for _, t := range req.AltTargets {
+				if f.Host == t {
+					rn.results = append(rn.results, f)
+				}
+			}
+		}
+	}
+	sc.runs = append(sc.runs, rn)
+	s.update(sc)
+	writeJSON(w, map[string]string{"scan_uuid": rn.uuid})
+}
+
+// transition moves the last run of the scan to the given status, if it is in
+// one of the statuses from
+func (s *Server) transition(w http.ResponseWriter, sc *scan, to string, from ...string) {
+	rn := sc.last()
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || rn != nil && rn.status == status
+	}
+	if !allowed {
+		writeError(w, http.StatusConflict, "Invalid scan status for this action")
+		return
+	}
+
+	now := s.Clock.Now()
+	switch to {
+	case statusPaused:
+		rn.remaining = rn.until.Sub(now)
+	case statusRunning:
+		rn.until = now.Add(rn.remaining)
+	}
+	rn.status = to
+	rn.modified = now
+	s.update(sc)
+}
+
+func (s *Server) scanDetail(w http.ResponseWriter, r *http.Request, sc *scan) {
+	rn := sc.last()
+	if h := r.URL.Query().Get("history_id"); h != "" {
+		rn = nil
+		for _, candidate := range sc.runs {
+			if strconv.FormatInt(candidate.historyID, 10) == h {
+				rn = candidate
+			}
+		}
+		if rn == nil {
+			writeError(w, http.StatusNotFound, "The requested file was not found")
+			return
+		}
+	}
+
+	detail := restuss.ScanDetail{
+		Info: restuss.Info{
+			Status:      statusEmpty,
+			UUID:        sc.UUID,
+			Name:        sc.Name,
+			ScannerName: "Local Scanner",
+			Targets:     sc.targets,
+		},
+		Hosts:           []restuss.Host{},
+		Vulnerabilities: []restuss.Vulnerability{},
+		History:         []restuss.ScanHistory{},
+	}
+	for _, h := range sc.runs {
+		detail.History = append(detail.History, restuss.ScanHistory{
+			HistoryID:            h.historyID,
+			UUID:                 h.uuid,
+			Status:               h.status,
+			CreationDate:         h.created.Unix(),
+			LastModificationDate: h.modified.Unix(),
+		})
+	}
+	if rn != nil {
+		detail.Info.Status = rn.status
+		detail.Info.UUID = rn.uuid
+		detail.Info.Targets = rn.targets
+		detail.Info.ScanStart = rn.created.Unix()
+		if rn.status == statusCompleted {
+			detail.Info.ScanEnd = rn.modified.Unix()
+			detail.Hosts, detail.Vulnerabilities = summarize(rn.results)
+		}
+	}
+	writeJSON(w, detail)
+}
+
+// summarize returns the hosts and the vulnerabilities, by plugin, of the
+// results of a run
+func summarize(results []restuss.HostFinding) ([]restuss.Host, []restuss.Vulnerability) {
+	hosts := []restuss.Host{}
+	for _, name := range hostNames(results) {
+		hosts = append(hosts, restuss.Host{ID: int64(len(hosts) + 1), Hostname: name})
+	}
+
+	byPlugin := map[int64]*restuss.Vulnerability{}
+	var ids []int64
+	for _, f := range results {
+		v, ok := byPlugin[f.PluginID]
+		if !ok {
+			v = &restuss.Vulnerability{PluginID: f.PluginID, PluginName: f.PluginName}
+			byPlugin[f.PluginID] = v
+			ids = append(ids, f.PluginID)
+		}
+		v.Count++
+		if int64(f.Severity) > v.Severity {
+			v.Severity = int64(f.Severity)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	vulns := []restuss.Vulnerability{}
+	for i, id := range ids {
+		v := *byPlugin[id]
+		v.VulnerabilityIndex = int64(i)
+		vulns = append(vulns, v)
+	}
+	return hosts, vulns
+}
+
+func hostNames(results []restuss.HostFinding) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, f := range results {
+		if !seen[f.Host] {
+			seen[f.Host] = true
+			names = append(names, f.Host)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) exportScan(w http.ResponseWriter, r *http.Request, sc *scan) {
+	var req restuss.ScanExport
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	var rn *run
+	h := r.URL.Query().Get("history_id")
+	for _, candidate := range sc.runs {
+		if candidate.status == statusCompleted && (h == "" || strconv.FormatInt(candidate.historyID, 10) == h) {
+			rn = candidate
+		}
+	}
+	if rn == nil {
+		writeError(w, http.StatusNotFound, "The requested file was not found")
+		return
+	}
+
+	var content []byte
+	switch req.Format {
+	case "nessus":
+		content, err = nessusReport(sc.Name, rn.results)
+	case "csv":
+		content, err = csvReport(rn.results)
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid 'format' field")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	id := s.newID()
+	s.exports[id] = &export{
+		scanID:  sc.ID,
+		ready:   s.Clock.Now().Add(s.ExportDuration),
+		content: content,
+	}
+	writeJSON(w, map[string]int64{"file": id})
+}
+
+func (s *Server) serveExport(w http.ResponseWriter, sc *scan, file, action string) {
+	id, err := strconv.ParseInt(file, 10, 64)
+	e, ok := s.exports[id]
+	if err != nil || !ok || e.scanID != sc.ID {
+		writeError(w, http.StatusNotFound, "The requested file was not found")
+		return
+	}
+	ready := !s.Clock.Now().Before(e.ready)
+
+	switch action {
+	case "status":
+		status := "loading"
+		if ready {
+			status = "ready"
+		}
+		writeJSON(w, map[string]string{"status": status})
+	case "download":
+		if !ready {
+			writeError(w, http.StatusConflict, "Report is still being generated")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(e.content)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// update moves the last run of the scan on to the status it has at the time
+// of the clock, and returns the scan as listed
+func (s *Server) update(sc *scan) restuss.PersistedScan {
+	now := s.Clock.Now()
+	if rn := sc.last(); rn != nil {
+		if rn.status == statusPending && !now.Before(rn.until) {
+			rn.status = statusRunning
+			rn.modified = rn.until
+			rn.until = rn.until.Add(s.RunDuration)
+		}
+		if rn.status == statusRunning && !now.Before(rn.until) {
+			rn.status = statusCompleted
+			rn.modified = rn.until
+		}
+		sc.Status = rn.status
+		sc.UUID = rn.uuid
+		sc.LastModificationDate = rn.modified.Unix()
+	}
+	return sc.PersistedScan
+}
+
+func (sc *scan) last() *run {
+	if len(sc.runs) == 0 {
+		return nil
+	}
+	return sc.runs[len(sc.runs)-1]
+}
+
+// active tells whether the scan has a run which didn't finish
+func (sc *scan) active() bool {
+	rn := sc.last()
+	return rn != nil && (rn.status == statusPending || rn.status == statusRunning || rn.status == statusPaused)
+}
+
+// nessusReport renders results in the .nessus (v2) format
+func nessusReport(name string, results []restuss.HostFinding) ([]byte, error) {
+	type reportItem struct {
+		Port         int      `xml:"port,attr"`
+		Service      string   `xml:"svc_name,attr"`
+		Protocol     string   `xml:"protocol,attr"`
+		Severity     int      `xml:"severity,attr"`
+		PluginID     int64    `xml:"pluginID,attr"`
+		PluginName   string   `xml:"pluginName,attr"`
+		CVSS3        *float32 `xml:"cvss3_base_score,omitempty"`
+		CVSS2        *float32 `xml:"cvss_base_score,omitempty"`
+		PluginOutput string   `xml:"plugin_output,omitempty"`
+	}
+	type reportHost struct {
+		Name  string       `xml:"name,attr"`
+		Items []reportItem `xml:"ReportItem"`
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"NessusClientData_v2"`
+		Report  struct {
+			Name  string       `xml:"name,attr"`
+			Hosts []reportHost `xml:"ReportHost"`
+		} `xml:"Report"`
+	}
+	doc.Report.Name = name
+	for _, host := range hostNames(results) {
+		rh := reportHost{Name: host}
+		for _, f := range results {
+			if f.Host == host {
+				rh.Items = append(rh.Items, reportItem{
+					Port:         f.Port,
+					Service:      f.Service,
+					Protocol:     f.Protocol,
+					Severity:     f.Severity,
+					PluginID:     f.PluginID,
+					PluginName:   f.PluginName,
+					CVSS3:        f.CVSS3,
+					CVSS2:        f.CVSS2,
+					PluginOutput: f.Output,
+				})
+			}
+		}
+		doc.Report.Hosts = append(doc.Report.Hosts, rh)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvReport renders results in the CSV format of Nessus exports
+func csvReport(results []restuss.HostFinding) ([]byte, error) {
+	risks := []string{"None", "Low", "Medium", "High", "Critical"}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"Plugin ID", "CVSS v3.0 Base Score", "Risk", "Host", "Protocol", "Port", "Name", "Plugin Output"})
+	for _, f := range results {
+		score, risk := "", strconv.Itoa(f.Severity)
+		if f.CVSS3 != nil {
+			score = strconv.FormatFloat(float64(*f.CVSS3), 'f', 1, 32)
+		}
+		if f.Severity >= 0 && f.Severity < len(risks) {
+			risk = risks[f.Severity]
+		}
+		_ = w.Write([]string{
+			strconv.FormatInt(f.PluginID, 10), score, risk, f.Host, f.Protocol,
+			strconv.Itoa(f.Port), f.PluginName, f.Output,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package restusstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/adevinta/restuss"
+)
+
+// filter is a condition of the searches of the API v3, either a group of
+// conditions or a comparison of a property
+type filter struct {
+	And      []filter    `json:"and"`
+	Or       []filter    `json:"or"`
+	Property string      `json:"property"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// match tells whether the object whose properties are returned by get
+// matches the filter
+func (f *filter) match(get func(property string) (string, bool)) (bool, error) {
+	switch {
+	case len(f.And) > 0:
+		for i := range f.And {
+			ok, err := f.And[i].match(get)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(f.Or) > 0:
+		for i := range f.Or {
+			ok, err := f.Or[i].match(get)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case f.Property == "":
+		return true, nil
+	}
+
+	v, ok := get(f.Property)
+	if !ok {
+		return false, fmt.Errorf("Unsupported property: %s", f.Property)
+	}
+	value := fmt.Sprint(f.Value)
+	switch f.Operator {
+	case "eq":
+		return strings.EqualFold(v, value), nil
+	case "neq":
+		return !strings.EqualFold(v, value), nil
+	}
+	return false, fmt.Errorf("Unsupported operator: %s", f.Operator)
+}
+
+// cursor is the state of a paginated search, its results being kept until
+// the last page is read. total is the number of results of the search, over
+// all the pages.
+type cursor struct {
+	key     string
+	results []interface{}
+	total   int
+}
+
+// serveSearch serves the asset and findings searches of the API v3
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	var key string
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/assets/search":
+		key = "assets"
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/findings/vulnerabilities/host/search":
+		key = "findings"
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var req struct {
+		Filter filter `json:"filter"`
+		Limit  int    `json:"limit"`
+		Next   string `json:"next"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	c, ok := s.cursors[req.Next]
+	switch {
+	case req.Next != "" && (!ok || c.key != key):
+		writeError(w, http.StatusBadRequest, "Invalid 'next' field")
+		return
+	case req.Next == "":
+		c = &cursor{key: key}
+		if key == "assets" {
+			err = s.searchAssets(c, &req.Filter)
+		} else {
+			err = s.searchFindings(c, &req.Filter)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.total = len(c.results)
+	}
+	delete(s.cursors, req.Next)
+
+	limit := s.PageSize
+	if req.Limit > 0 && (limit <= 0 || req.Limit < limit) {
+		limit = req.Limit
+	}
+	page := c.results
+	if page == nil {
+		page = []interface{}{}
+	}
+	pagination := restuss.Pagination{Limit: limit, Total: c.total}
+	if limit > 0 && len(page) > limit {
+		page, c.results = page[:limit], page[limit:]
+		pagination.Next = fmt.Sprintf("%016x", s.newID())
+		s.cursors[pagination.Next] = c
+	}
+	writeJSON(w, map[string]interface{}{key: page, "pagination": pagination})
+}
+
+func (s *Server) searchAssets(c *cursor, f *filter) error {
+	for _, a := range s.assets {
+		ok, err := f.match(func(property string) (string, bool) {
+			switch property {
+			case "id":
+				return a.ID, true
+			case "name":
+				return a.Name, true
+			case "network.id":
+				return a.Network.ID, true
+			}
+			return "", false
+		})
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.results = append(c.results, a)
+		}
+	}
+	return nil
+}
+
+func (s *Server) searchFindings(c *cursor, f *filter) error {
+	for _, finding := range s.findings {
+		var network string
+		for _, a := range s.assets {
+			if finding.Asset.ID != "" && a.ID == finding.Asset.ID ||
+				finding.Asset.ID == "" && a.Name == finding.Asset.Name {
+				network = a.Network.ID
+			}
+		}
+		ok, err := f.match(func(property string) (string, bool) {
+			switch property {
+			case "asset.id":
+				return finding.Asset.ID, true
+			case "asset.name":
+				return finding.Asset.Name, true
+			case "asset.network.id":
+				return network, true
+			case "state":
+				return finding.State, true
+			case "severity":
+				return fmt.Sprint(finding.Severity), true
+			}
+			return "", false
+		})
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.results = append(c.results, finding)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// Package restusstest provides an in-memory fake of the Nessus and
+// Tenable.io APIs, to test code using restuss without a scanner.
+//
+// The fake keeps scans, templates, plugins, policies, assets and findings in
+// memory. Scans go from pending to running to completed as its clock is
+// advanced, and their exports are rendered from the results set for them:
+//
+//	s := restusstest.NewServer()
+//	defer s.Close()
+//	c := s.Client()
+//
+//	scan, _ := c.CreateScan(&restuss.Scan{TemplateUUID: restusstest.TemplateBasic, ...})
+//	s.SetResults(scan.ID, findings)
+//	_ = c.LaunchScan(scan.ID)
+//	s.Clock.Advance(time.Hour)
+//	detail, _ := c.GetScanByID(scan.ID) // completed
+package restusstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+// Templates of the scans created by default
+const (
+	TemplateBasic    = "731a8e52-3ea6-a291-ec0a-d2ff0619c19d7bd788d6be818b65"
+	TemplateAdvanced = "ad629e16-03b6-8c1d-cef6-ef8c9dd3c658d24bd260ef5f9e66"
+)
+
+// Clock is the time of a Server, which only moves when told to. It is safe
+// for concurrent use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a Clock set to t
+func NewClock(t time.Time) *Clock {
+	return &Clock{now: t}
+}
+
+// Now returns the time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the time of the clock
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Server is a fake Nessus and Tenable.io API served over HTTP. Its exported
+// fields can be changed before making requests, the state is set through its
+// methods. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the API
+	URL string
+	// Clock drives the status of the scans and exports
+	Clock *Clock
+
+	// AccessKey and SecretKey are the API keys accepted, any are when empty
+	AccessKey string
+	SecretKey string
+	// Username and Password are the credentials accepted for basic
+	// authentication and sessions, any are when empty
+	Username string
+	Password string
+
+	// PendingDuration is the time a launched scan stays pending, 10 seconds
+	// by default
+	PendingDuration time.Duration
+	// RunDuration is the time a scan runs before completing, 5 minutes by
+	// default
+	RunDuration time.Duration
+	// ExportDuration is the time an export takes to be ready, none by
+	// default
+	ExportDuration time.Duration
+	// PageSize is the number of results per page of the searches, 200 by
+	// default, unless the request sets a lower limit
+	PageSize int
+
+	ts *httptest.Server
+
+	mu         sync.Mutex
+	nextID     int64
+	sessions   map[string]bool
+	throttled  int
+	retryAfter time.Duration
+	templates  []restuss.ScanTemplate
+	scans      map[int64]*scan
+	exports    map[int64]*export
+	plugins    map[int64]restuss.Plugin
+	policies   map[int64]restuss.Policy
+	assets     []restuss.Asset
+	findings   []restuss.Finding
+	cursors    map[string]*cursor
+}
+
+// NewServer starts a Server, which must be closed when done. Its clock
+// starts on 1 January 2024 and it has the basic and advanced scan templates.
+func NewServer() *Server {
+	s := &Server{
+		Clock:           NewClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		PendingDuration: 10 * time.Second,
+		RunDuration:     5 * time.Minute,
+		PageSize:        200,
+		sessions:        map[string]bool{},
+		templates: []restuss.ScanTemplate{
+			{UUID: TemplateBasic, Name: "basic", Title: "Basic Network Scan"},
+			{UUID: TemplateAdvanced, Name: "advanced", Title: "Advanced Scan"},
+		},
+		scans:    map[int64]*scan{},
+		exports:  map[int64]*export{},
+		plugins:  map[int64]restuss.Plugin{},
+		policies: map[int64]restuss.Policy{},
+		cursors:  map[string]*cursor{},
+	}
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// Client returns a client of the server authenticated with its API keys
+func (s *Server) Client() *restuss.NessusClient {
+	access, secret := s.AccessKey, s.SecretKey
+	if access == "" {
+		access, secret = "access", "secret"
+	}
+	c, err := restuss.NewClient(restuss.NewKeyAuthProvider(access, secret), s.URL, false)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// InjectRateLimit makes the server reject the next n requests with status
+// code 429, asking to try again after retryAfter
+func (s *Server) InjectRateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled = n
+	s.retryAfter = retryAfter
+}
+
+// AddTemplate adds a scan template
+func (s *Server) AddTemplate(t restuss.ScanTemplate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = append(s.templates, t)
+}
+
+// AddPlugin adds a plugin, replacing any with the same ID
+func (s *Server) AddPlugin(p restuss.Plugin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plugins[p.ID] = p
+}
+
+// AddPolicy adds a policy, returning its ID
+func (s *Server) AddPolicy(p restuss.Policy) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.ID = s.newID()
+	if p.UUID == "" {
+		p.UUID = fakeUUID(p.ID)
+	}
+	s.policies[p.ID] = p
+	return p.ID
+}
+
+// AddAsset adds an asset
+func (s *Server) AddAsset(a restuss.Asset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.ID == "" {
+		a.ID = fakeUUID(s.newID())
+	}
+	s.assets = append(s.assets, a)
+}
+
+// AddFindings adds findings, linked to the assets by the ID or name of their
+// Asset attributes
+func (s *Server) AddFindings(findings ...restuss.Finding) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.findings = append(s.findings, findings...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.throttled > 0 {
+		s.throttled--
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(s.retryAfter.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "Too many requests")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if segments[0] == "session" && len(segments) == 1 {
+		s.session(w, r)
+		return
+	}
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "Invalid Credentials")
+		return
+	}
+
+	switch segments[0] {
+	case "scans":
+		s.serveScans(w, r, segments[1:])
+	case "editor":
+		if r.Method == http.MethodGet && r.URL.Path == "/editor/scan/templates" {
+			writeJSON(w, map[string]interface{}{"templates": s.templates})
+			return
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+	case "plugins":
+		s.servePlugins(w, r, segments[1:])
+	case "policies":
+		s.servePolicies(w, r, segments[1:])
+	case "api":
+		s.serveSearch(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// session serves the login, logout and user of sessions
+func (s *Server) session(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var creds struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&creds)
+		if err != nil || !s.validUser(creds.Username, creds.Password) {
+			writeError(w, http.StatusUnauthorized, "Invalid Credentials")
+			return
+		}
+		token := fmt.Sprintf("%048x", s.newID())
+		s.sessions[token] = true
+		writeJSON(w, map[string]string{"token": token})
+	case http.MethodGet:
+		if !s.authenticated(r) {
+			writeError(w, http.StatusUnauthorized, "Invalid Credentials")
+			return
+		}
+		writeJSON(w, map[string]string{"username": s.Username})
+	case http.MethodDelete:
+		delete(s.sessions, sessionToken(r))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+// authenticated tells whether the request carries valid API keys, basic
+// authentication or session token
+func (s *Server) authenticated(r *http.Request) bool {
+	if keys := r.Header.Get("X-ApiKeys"); keys != "" {
+		var access, secret string
+		for _, kv := range strings.Split(keys, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(kv), "=")
+			switch k {
+			case "accessKey":
+				access = v
+			case "secretKey":
+				secret = v
+			}
+		}
+		if s.AccessKey == "" {
+			return access != "" && secret != ""
+		}
+		return access == s.AccessKey && secret == s.SecretKey
+	}
+	if username, password, ok := r.BasicAuth(); ok {
+		return s.validUser(username, password)
+	}
+	return s.sessions[sessionToken(r)]
+}
+
+func (s *Server) validUser(username, password string) bool {
+	if s.Username == "" {
+		return username != ""
+	}
+	return username == s.Username && password == s.Password
+}
+
+func sessionToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("X-Cookie"), "token=")
+}
+
+func (s *Server) servePlugins(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet || len(segments) != 2 || segments[0] != "plugin" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	id, err := strconv.ParseInt(segments[1], 10, 64)
+	p, ok := s.plugins[id]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "Plugin not found")
+		return
+	}
+	writeJSON(w, p)
+}
+
+func (s *Server) servePolicies(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet || len(segments) == 0 || len(segments) > 2 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	id, err := strconv.ParseInt(segments[0], 10, 64)
+	p, ok := s.policies[id]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "Policy not found")
+		return
+	}
+
+	if len(segments) == 1 {
+		writeJSON(w, p)
+		return
+	}
+	if segments[1] != "export" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, "<?xml version=\"1.0\" ?>\n<NessusClientData_v2><Policy><policyName>%s</policyName></Policy></NessusClientData_v2>\n",
+		xmlEscape(p.Settings.Name))
+}
+
+// newID returns a new ID, unique across all the objects of the server
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// fakeUUID returns a UUID derived from an ID
+func fakeUUID(id int64) string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", id, id)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,224 @@
+package restusstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adevinta/restuss"
+)
+
+func TestScanLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	scan, err := c.CreateScan(&restuss.Scan{
+		TemplateUUID: TemplateBasic,
+		Settings:     restuss.ScanSettings{Name: "staging", Targets: "a.example.com,b.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Error creating scan: %v", err)
+	}
+	s.SetResults(scan.ID, []restuss.HostFinding{
+		{Host: "a.example.com", PluginID: 10, PluginName: "TLS", Port: 443, Protocol: "tcp", Severity: 2},
+		{Host: "b.example.com", PluginID: 20, PluginName: "SSH", Port: 22, Protocol: "tcp", Severity: 3},
+	})
+
+	err = c.LaunchScan(scan.ID)
+	if err != nil {
+		t.Fatalf("Error launching scan: %v", err)
+	}
+	steps := []struct {
+		action   func(int64) error
+		advance  time.Duration
+		expected string
+	}{
+		{nil, 0, "pending"},
+		{nil, s.PendingDuration, "running"},
+		{c.PauseScan, time.Hour, "paused"},
+		{c.ResumeScan, s.RunDuration - time.Second, "running"},
+		{nil, time.Second, "completed"},
+	}
+	for _, step := range steps {
+		if step.action != nil {
+			err = step.action(scan.ID)
+			if err != nil {
+				t.Fatalf("Error changing scan status: %v", err)
+			}
+		}
+		s.Clock.Advance(step.advance)
+		detail, err := c.GetScanByID(scan.ID)
+		if err != nil {
+			t.Fatalf("Error getting scan: %v", err)
+		}
+		if detail.Info.Status != step.expected {
+			t.Fatalf("got status %q, expected %q", detail.Info.Status, step.expected)
+		}
+	}
+
+	detail, err := c.GetScanByID(scan.ID)
+	if err != nil {
+		t.Fatalf("Error getting scan: %v", err)
+	}
+	if len(detail.Hosts) != 2 || len(detail.Vulnerabilities) != 2 || len(detail.History) != 1 {
+		t.Fatalf("got %d hosts, %d vulnerabilities and %d runs, expected 2, 2 and 1",
+			len(detail.Hosts), len(detail.Vulnerabilities), len(detail.History))
+	}
+
+	findings, err := c.GetHostFindings(scan.ID, detail.History[0].HistoryID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error getting findings: %v", err)
+	}
+	if len(findings) != 2 || findings[1].Host != "b.example.com" || findings[1].Severity != 3 {
+		t.Fatalf("got findings %+v", findings)
+	}
+
+	// Runs against alternative targets only report their findings.
+	uuid, err := c.LaunchScanWithTargets(scan.ID, []string{"a.example.com"})
+	if err != nil {
+		t.Fatalf("Error launching scan: %v", err)
+	}
+	s.Clock.Advance(time.Hour)
+	detail, err = c.GetScanByID(scan.ID)
+	if err != nil {
+		t.Fatalf("Error getting scan: %v", err)
+	}
+	if detail.Info.UUID != uuid || len(detail.Hosts) != 1 {
+		t.Fatalf("got run %s with %d hosts, expected %s with 1", detail.Info.UUID, len(detail.Hosts), uuid)
+	}
+}
+
+func TestIncrementalScanList(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	scan, err := c.CreateScanContext(ctx, &restuss.Scan{
+		TemplateUUID: TemplateBasic,
+		Settings:     restuss.ScanSettings{Name: "staging", Targets: "a.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Error creating scan: %v", err)
+	}
+
+	list, err := c.GetScanListContext(ctx, 0)
+	if err != nil {
+		t.Fatalf("Error listing scans: %v", err)
+	}
+	if len(list.Scans) != 1 || list.Scans[0].Status != "empty" {
+		t.Fatalf("got scans %+v, expected the new scan", list.Scans)
+	}
+
+	// The scan changes without the clock moving.
+	err = c.LaunchScanContext(ctx, scan.ID)
+	if err != nil {
+		t.Fatalf("Error launching scan: %v", err)
+	}
+	list, err = c.GetScanListContext(ctx, list.Timestamp)
+	if err != nil {
+		t.Fatalf("Error listing scans: %v", err)
+	}
+	if len(list.Scans) != 1 || list.Scans[0].Status != "pending" {
+		t.Fatalf("got scans %+v, expected the launched scan", list.Scans)
+	}
+
+	s.Clock.Advance(time.Hour)
+	list, err = c.GetScanListContext(ctx, list.Timestamp)
+	if err != nil {
+		t.Fatalf("Error listing scans: %v", err)
+	}
+	if len(list.Scans) != 1 || list.Scans[0].Status != "completed" {
+		t.Fatalf("got scans %+v, expected the completed scan", list.Scans)
+	}
+
+	list, err = c.GetScanListContext(ctx, list.Timestamp)
+	if err != nil {
+		t.Fatalf("Error listing scans: %v", err)
+	}
+	if len(list.Scans) != 0 {
+		t.Fatalf("got scans %+v, expected none modified", list.Scans)
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.PageSize = 2
+	c := s.Client()
+
+	s.AddAsset(restuss.Asset{ID: "asset-1", Name: "web"})
+	for i := 0; i < 5; i++ {
+		f := restuss.Finding{Severity: i, State: restuss.FindingStateOpen}
+		f.Asset.ID = "asset-1"
+		f.Asset.Name = "web"
+		if i == 4 {
+			f.State = restuss.FindingStateFixed
+		}
+		s.AddFindings(f)
+	}
+
+	asset, err := c.GetAssetByName(context.Background(), "web")
+	if err != nil || asset.ID != "asset-1" {
+		t.Fatalf("got asset %+v, error: %v", asset, err)
+	}
+	findings, err := c.SearchFindingsByAssetName(context.Background(), "web", &restuss.FindingsOptions{
+		States: []string{restuss.FindingStateOpen},
+	})
+	if err != nil {
+		t.Fatalf("Error searching findings: %v", err)
+	}
+	if len(findings) != 4 {
+		t.Fatalf("got %d findings, expected 4", len(findings))
+	}
+
+	// Every page carries the total of the search, not the results left.
+	var next string
+	for page := 0; page < 3; page++ {
+		body, _ := json.Marshal(map[string]string{"next": next})
+		r := httptest.NewRequest(http.MethodPost, "/api/v3/findings/vulnerabilities/host/search", bytes.NewReader(body))
+		r.Header.Set("X-ApiKeys", "accessKey=access;secretKey=secret")
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		var res struct {
+			Pagination restuss.Pagination `json:"pagination"`
+		}
+		err = json.NewDecoder(w.Body).Decode(&res)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res.Pagination.Total != 5 {
+			t.Fatalf("got total %d on page %d, expected: 5", res.Pagination.Total, page)
+		}
+		next = res.Pagination.Next
+	}
+	if next != "" {
+		t.Fatalf("got next page %s after the last one", next)
+	}
+}
+
+func TestInjectRateLimit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	rateLimited := 0
+	c.SetHooks(&restuss.Hooks{OnRetry: func(e *restuss.RequestEvent) {
+		if e.StatusCode == http.StatusTooManyRequests {
+			rateLimited++
+		}
+	}})
+
+	s.InjectRateLimit(2, 0)
+	templates, err := c.GetScanTemplates()
+	if err != nil {
+		t.Fatalf("Error getting templates: %v", err)
+	}
+	if len(templates) != 2 || rateLimited != 2 {
+		t.Fatalf("got %d templates after %d rate limited requests, expected 2 and 2", len(templates), rateLimited)
+	}
+}
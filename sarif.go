@@ -0,0 +1,343 @@
+package restuss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog represents a SARIF 2.1.0 log
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun represents a run of a SARIF log
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool represents the tool of a SARIF run
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver represents the tool component which produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule represents a rule, a plugin in Nessus terms
+type SARIFRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription *SARIFMessage          `json:"shortDescription,omitempty"`
+	FullDescription  *SARIFMessage          `json:"fullDescription,omitempty"`
+	Help             *SARIFMessage          `json:"help,omitempty"`
+	HelpURI          string                 `json:"helpUri,omitempty"`
+	DefaultConfig    *SARIFRuleConfig       `json:"defaultConfiguration,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SARIFRuleConfig represents the default configuration of a rule
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage represents a SARIF message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult represents a result, a finding on a host in Nessus terms
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+// SARIFLocation represents the location of a result
+type SARIFLocation struct {
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+}
+
+// SARIFLogicalLocation represents a host, port and protocol as a logical location
+type SARIFLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFBuilder accumulates findings and builds a SARIF log out of them, with
+// one rule per plugin.
+type SARIFBuilder struct {
+	rules   []SARIFRule
+	index   map[string]int
+	results []SARIFResult
+}
+
+// NewSARIFBuilder returns an empty SARIFBuilder
+func NewSARIFBuilder() *SARIFBuilder {
+	return &SARIFBuilder{index: map[string]int{}}
+}
+
+// SARIFLevel maps a Nessus severity to a SARIF level
+func SARIFLevel(severity int) string {
+	switch {
+	case severity >= 3:
+		return "error"
+	case severity == 2:
+		return "warning"
+	case severity == 1:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// AddFindings adds findings, assetName is used as host when the findings
+// don't carry their asset attributes.
+func (b *SARIFBuilder) AddFindings(assetName string, findings []Finding) {
+	for _, f := range findings {
+		d := f.Definition
+		props := map[string]interface{}{}
+		if d.CVSS3.BaseScore != nil {
+			props["cvss3_base_score"] = *d.CVSS3.BaseScore
+			props["security-severity"] = strconv.FormatFloat(float64(*d.CVSS3.BaseScore), 'f', 1, 32)
+		}
+		if d.CVSS2.BaseScore != nil {
+			props["cvss2_base_score"] = *d.CVSS2.BaseScore
+		}
+		if d.CVSS3.Vector != "" {
+			props["cvss3_vector"] = d.CVSS3.Vector
+		}
+		if len(d.CWE) > 0 {
+			props["cwe"] = d.CWE
+		}
+		if len(d.CVE) > 0 {
+			props["cve"] = d.CVE
+		}
+		if len(d.SeeAlso) > 0 {
+			props["see_also"] = d.SeeAlso
+		}
+		rule := SARIFRule{
+			ID:               strconv.Itoa(d.ID),
+			Name:             d.Name,
+			ShortDescription: sarifMessage(d.Synopsis),
+			FullDescription:  sarifMessage(d.Description),
+			Help:             sarifMessage(d.Solution),
+			Properties:       props,
+		}
+		if len(d.SeeAlso) > 0 {
+			rule.HelpURI = d.SeeAlso[0]
+		}
+
+		host := findingHost(f)
+		if host == "" {
+			host = assetName
+		}
+		msg := d.Name
+		if f.Output != "" {
+			msg = f.Output
+		}
+		b.add(rule, f.Severity, host, f.Port, f.Protocol, msg)
+	}
+}
+
+// AddScanDetail adds the vulnerabilities of a scan. Scan details only give
+// counts per plugin, so results are located on the scan itself; use
+// AddPluginOutput to locate them on hosts.
+func (b *SARIFBuilder) AddScanDetail(detail *ScanDetail) {
+	name := detail.Info.Name
+	if name == "" {
+		name = "scan-" + strconv.FormatInt(detail.ID, 10)
+	}
+	for _, v := range detail.Vulnerabilities {
+		rule := SARIFRule{
+			ID:         strconv.FormatInt(v.PluginID, 10),
+			Name:       v.PluginName,
+			Properties: map[string]interface{}{"plugin_family": v.PluginFamily},
+		}
+		msg := fmt.Sprintf("%s (%d occurrences)", v.PluginName, v.Count)
+		b.add(rule, int(v.Severity), name, 0, "", msg)
+	}
+}
+
+// AddPluginOutput adds the output of a plugin, with one result per host and
+// port it was reported on.
+func (b *SARIFBuilder) AddPluginOutput(plugin *Plugin, output *PluginOutputResponse) {
+	rule := SARIFRule{ID: strconv.FormatInt(plugin.ID, 10), Name: plugin.Name}
+	if plugin.FamilyName != "" {
+		rule.Properties = map[string]interface{}{"plugin_family": plugin.FamilyName}
+	}
+	for _, a := range plugin.Attributes {
+		switch a.Name {
+		case "synopsis":
+			rule.ShortDescription = sarifMessage(a.Value)
+		case "description":
+			rule.FullDescription = sarifMessage(a.Value)
+		case "solution":
+			rule.Help = sarifMessage(a.Value)
+		}
+	}
+
+	for _, o := range output.Output {
+		msg := o.Output
+		if msg == "" {
+			msg = plugin.Name
+		}
+		for _, l := range pluginOutputLocations(o) {
+			b.add(rule, o.Severity, l.host, l.port, l.protocol, msg)
+		}
+	}
+}
+
+// Log returns the SARIF log of the results added so far
+func (b *SARIFBuilder) Log() *SARIFLog {
+	rules := make([]SARIFRule, len(b.rules))
+	copy(rules, b.rules)
+	results := make([]SARIFResult, len(b.results))
+	copy(results, b.results)
+	return &SARIFLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "Nessus",
+				InformationURI: "https://www.tenable.com/products/nessus",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// WriteTo writes the SARIF log as JSON to w
+func (b *SARIFBuilder) WriteTo(w io.Writer) (int64, error) {
+	buf, err := json.MarshalIndent(b.Log(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+func (b *SARIFBuilder) add(rule SARIFRule, severity int, host string, port int, protocol, msg string) {
+	level := SARIFLevel(severity)
+	i, ok := b.index[rule.ID]
+	if !ok {
+		rule.DefaultConfig = &SARIFRuleConfig{Level: level}
+		i = len(b.rules)
+		b.index[rule.ID] = i
+		b.rules = append(b.rules, rule)
+	}
+
+	fqn := host
+	if port > 0 || protocol != "" {
+		fqn = fmt.Sprintf("%s:%d/%s", host, port, strings.ToLower(protocol))
+	}
+	b.results = append(b.results, SARIFResult{
+		RuleID:    rule.ID,
+		RuleIndex: i,
+		Level:     level,
+		Message:   SARIFMessage{Text: msg},
+		Locations: []SARIFLocation{{LogicalLocations: []SARIFLogicalLocation{{
+			Name:               host,
+			FullyQualifiedName: fqn,
+			Kind:               "resource",
+		}}}},
+		// The fingerprint only depends on what identifies the finding, so
+		// the same vulnerability on the same service keeps its identity
+		// across runs and is deduplicated.
+		PartialFingerprints: map[string]string{
+			"nessusFinding/v1": sarifFingerprint(rule.ID, host, port, protocol),
+		},
+	})
+}
+
+func sarifFingerprint(ruleID, host string, port int, protocol string) string {
+	key := strings.Join([]string{ruleID, strings.ToLower(host), strconv.Itoa(port), strings.ToLower(protocol)}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func sarifMessage(text string) *SARIFMessage {
+	if text == "" {
+		return nil
+	}
+	return &SARIFMessage{Text: text}
+}
+
+// findingHost returns the most specific host name of a finding
+func findingHost(f Finding) string {
+	switch {
+	case len(f.Asset.FQDN) > 0:
+		return f.Asset.FQDN[0]
+	case len(f.Asset.IPv4) > 0:
+		return f.Asset.IPv4[0]
+	case len(f.Asset.IPv6) > 0:
+		return f.Asset.IPv6[0]
+	}
+	return f.Asset.Name
+}
+
+type outputLocation struct {
+	host     string
+	port     int
+	protocol string
+}
+
+// pluginOutputLocations extracts hosts and ports from a plugin output. Ports
+// are returned as a map keyed by "port / protocol / service" listing the
+// hosts the output was seen on.
+func pluginOutputLocations(o PluginOutput) []outputLocation {
+	var locations []outputLocation
+	ports, _ := o.Ports.(map[string]interface{})
+	keys := make([]string, 0, len(ports))
+	for k := range ports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		parts := strings.Split(k, "/")
+		l := outputLocation{}
+		l.port, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+		if len(parts) > 1 {
+			l.protocol = strings.TrimSpace(parts[1])
+		}
+		hosts, _ := ports[k].([]interface{})
+		for _, h := range hosts {
+			hm, _ := h.(map[string]interface{})
+			name, _ := hm["hostname"].(string)
+			if name == "" {
+				continue
+			}
+			l.host = name
+			locations = append(locations, l)
+		}
+	}
+
+	if len(locations) == 0 {
+		for _, h := range strings.Split(o.Hosts, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				locations = append(locations, outputLocation{host: h})
+			}
+		}
+	}
+	return locations
+}
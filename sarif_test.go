@@ -0,0 +1,52 @@
+package restuss
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIFBuilder(t *testing.T) {
+	score := float32(9.8)
+	var f Finding
+	f.Severity = 4
+	f.Port = 443
+	f.Protocol = "TCP"
+	f.Asset.FQDN = StringList{"web.example.com"}
+	f.Definition.ID = 19506
+	f.Definition.Name = "Critical thing"
+	f.Definition.CVSS3.BaseScore = &score
+
+	var output PluginOutputResponse
+	err := json.Unmarshal([]byte(`{"outputs": [{
+		"plugin_output": "found",
+		"severity": 4,
+		"ports": {"443 / tcp / www": [{"hostname": "WEB.example.com"}]}
+	}]}`), &output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	b := NewSARIFBuilder()
+	b.AddFindings("", []Finding{f})
+	b.AddPluginOutput(&Plugin{ID: 19506, Name: "Critical thing"}, &output)
+	log := b.Log()
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 {
+		t.Fatalf("got %d rules, expected: 1", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, expected: 2", len(run.Results))
+	}
+	for _, r := range run.Results {
+		if r.Level != "error" {
+			t.Fatalf("got level: %v, expected: error", r.Level)
+		}
+	}
+	if run.Results[0].PartialFingerprints["nessusFinding/v1"] != run.Results[1].PartialFingerprints["nessusFinding/v1"] {
+		t.Fatalf("fingerprints of the same finding differ: %v", run.Results)
+	}
+	if got := run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName; got != "web.example.com:443/tcp" {
+		t.Fatalf("got location: %v, expected: web.example.com:443/tcp", got)
+	}
+}
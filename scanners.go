@@ -0,0 +1,249 @@
+package restuss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ListScanners returns all the scanners
+func (c *NessusClient) ListScanners() ([]Scanner, error) {
+	return c.ListScannersContext(context.Background())
+}
+
+// ListScannersContext returns all the scanners using the given context.
+func (c *NessusClient) ListScannersContext(ctx context.Context) ([]Scanner, error) {
+	return c.getScanners(ctx, "/scanners")
+}
+
+// GetScanner retrieves a scanner by ID
+func (c *NessusClient) GetScanner(ID int64) (*Scanner, error) {
+	return c.GetScannerContext(context.Background(), ID)
+}
+
+// GetScannerContext retrieves a scanner by ID using the given context.
+func (c *NessusClient) GetScannerContext(ctx context.Context, ID int64) (*Scanner, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/scanners/%d", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scanner{}
+	err = c.performCallAndReadResponse(req, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetScannerScans returns the scans currently running on a scanner
+func (c *NessusClient) GetScannerScans(ID int64) ([]ScannerScan, error) {
+	return c.GetScannerScansContext(context.Background(), ID)
+}
+
+// GetScannerScansContext returns the scans currently running on a scanner using the given context.
+func (c *NessusClient) GetScannerScansContext(ctx context.Context, ID int64) ([]ScannerScan, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/scanners/%d/scans", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Scans []ScannerScan `json:"scans"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Scans, nil
+}
+
+// SelectScanner returns the linked and online scanner running the fewest scans
+func (c *NessusClient) SelectScanner() (*Scanner, error) {
+	return c.SelectScannerContext(context.Background())
+}
+
+// SelectScannerContext returns the linked and online scanner running the
+// fewest scans using the given context.
+func (c *NessusClient) SelectScannerContext(ctx context.Context) (*Scanner, error) {
+	scanners, err := c.ListScannersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *Scanner
+	bestRunning := 0
+	for i := range scanners {
+		s := &scanners[i]
+		if s.Status != "on" || s.Linked == 0 || s.Pool {
+			continue
+		}
+		running, err := c.GetScannerScansContext(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		if best == nil || len(running) < bestRunning {
+			best, bestRunning = s, len(running)
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("No healthy scanner available")
+	}
+
+	return best, nil
+}
+
+// ListScannerGroups returns all the scanner groups
+func (c *NessusClient) ListScannerGroups() ([]ScannerGroup, error) {
+	return c.ListScannerGroupsContext(context.Background())
+}
+
+// ListScannerGroupsContext returns all the scanner groups using the given context.
+func (c *NessusClient) ListScannerGroupsContext(ctx context.Context) ([]ScannerGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/scanner-groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Groups []ScannerGroup `json:"scanner_pools"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Groups, nil
+}
+
+// GetScannerGroup retrieves a scanner group by ID
+func (c *NessusClient) GetScannerGroup(ID int64) (*ScannerGroup, error) {
+	return c.GetScannerGroupContext(context.Background(), ID)
+}
+
+// GetScannerGroupContext retrieves a scanner group by ID using the given context.
+func (c *NessusClient) GetScannerGroupContext(ctx context.Context, ID int64) (*ScannerGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/scanner-groups/%d", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &ScannerGroup{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// CreateScannerGroup creates a scanner group
+func (c *NessusClient) CreateScannerGroup(group *ScannerGroup) (*ScannerGroup, error) {
+	return c.CreateScannerGroupContext(context.Background(), group)
+}
+
+// CreateScannerGroupContext creates a scanner group using the given context.
+func (c *NessusClient) CreateScannerGroupContext(ctx context.Context, group *ScannerGroup) (*ScannerGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/scanner-groups", group)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &ScannerGroup{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// EditScannerGroup renames the scanner group with the given ID
+func (c *NessusClient) EditScannerGroup(ID int64, name string) error {
+	return c.EditScannerGroupContext(context.Background(), ID, name)
+}
+
+// EditScannerGroupContext renames the scanner group with the given ID using the given context.
+func (c *NessusClient) EditScannerGroupContext(ctx context.Context, ID int64, name string) error {
+	payload := map[string]string{"name": name}
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/scanner-groups/%d", ID), payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// DeleteScannerGroup removes the scanner group with the given ID
+func (c *NessusClient) DeleteScannerGroup(ID int64) error {
+	return c.DeleteScannerGroupContext(context.Background(), ID)
+}
+
+// DeleteScannerGroupContext removes the scanner group with the given ID using the given context.
+func (c *NessusClient) DeleteScannerGroupContext(ctx context.Context, ID int64) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/scanner-groups/%d", ID), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ListScannerGroupScanners returns the scanners member of a scanner group
+func (c *NessusClient) ListScannerGroupScanners(groupID int64) ([]Scanner, error) {
+	return c.ListScannerGroupScannersContext(context.Background(), groupID)
+}
+
+// ListScannerGroupScannersContext returns the scanners member of a scanner group using the given context.
+func (c *NessusClient) ListScannerGroupScannersContext(ctx context.Context, groupID int64) ([]Scanner, error) {
+	return c.getScanners(ctx, fmt.Sprintf("/scanner-groups/%d/scanners", groupID))
+}
+
+// AddScannerToGroup adds a scanner to a scanner group
+func (c *NessusClient) AddScannerToGroup(groupID, scannerID int64) error {
+	return c.AddScannerToGroupContext(context.Background(), groupID, scannerID)
+}
+
+// AddScannerToGroupContext adds a scanner to a scanner group using the given context.
+func (c *NessusClient) AddScannerToGroupContext(ctx context.Context, groupID, scannerID int64) error {
+	path := fmt.Sprintf("/scanner-groups/%d/scanners/%d", groupID, scannerID)
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// RemoveScannerFromGroup removes a scanner from a scanner group
+func (c *NessusClient) RemoveScannerFromGroup(groupID, scannerID int64) error {
+	return c.RemoveScannerFromGroupContext(context.Background(), groupID, scannerID)
+}
+
+// RemoveScannerFromGroupContext removes a scanner from a scanner group using the given context.
+func (c *NessusClient) RemoveScannerFromGroupContext(ctx context.Context, groupID, scannerID int64) error {
+	path := fmt.Sprintf("/scanner-groups/%d/scanners/%d", groupID, scannerID)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+func (c *NessusClient) getScanners(ctx context.Context, path string) ([]Scanner, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Scanners []Scanner `json:"scanners"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Scanners, nil
+}
@@ -0,0 +1,84 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelectScanner(t *testing.T) {
+	running := map[string]int{
+		"/scanners/1/scans": 3,
+		"/scanners/4/scans": 1,
+		"/scanners/5/scans": 2,
+	}
+	var listed []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.URL.Path == "/scanners" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"scanners": []Scanner{
+						{ID: 1, Status: "on", Linked: 1},
+						{ID: 2, Status: "off", Linked: 1},
+						{ID: 3, Status: "on", Linked: 0},
+						{ID: 4, Status: "on", Linked: 1},
+						{ID: 5, Status: "on", Linked: 1},
+						{ID: 6, Status: "on", Linked: 1, Pool: true},
+					},
+				})
+				return
+			}
+			n, ok := running[r.URL.Path]
+			if !ok {
+				t.Errorf("got request to %s for an unhealthy scanner", r.URL.Path)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"scans": nil})
+				return
+			}
+			listed = append(listed, r.URL.Path)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"scans": make([]ScannerScan, n)})
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	s, err := c.SelectScanner()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.ID != 4 {
+		t.Fatalf("got scanner: %d, expected: 4", s.ID)
+	}
+	if len(listed) != len(running) {
+		t.Fatalf("got scans listed for: %v, expected the %d healthy scanners", listed, len(running))
+	}
+}
+
+func TestSelectScannerNoneHealthy(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/scanners" {
+				t.Errorf("got request to %s, expected only the scanner list", r.URL.Path)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"scanners": []Scanner{{ID: 1, Status: "off", Linked: 1}},
+			})
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	if _, err := c.SelectScanner(); err == nil {
+		t.Fatalf("got no error, expected no healthy scanner")
+	}
+}
@@ -0,0 +1,211 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// ScanChangeType describes how a scan changed between two syncs
+type ScanChangeType string
+
+const (
+	// ScanAdded is reported for scans not seen on previous syncs
+	ScanAdded ScanChangeType = "added"
+	// ScanModified is reported for known scans modified since the last sync
+	ScanModified ScanChangeType = "modified"
+	// ScanDeleted is reported for known scans moved to the trash or removed
+	ScanDeleted ScanChangeType = "deleted"
+)
+
+// ScanChange represents a scan that changed since the last sync
+type ScanChange struct {
+	Type ScanChangeType
+	Scan *PersistedScan
+}
+
+// SyncCursor holds the state a ScanSyncer needs between calls: the server
+// timestamp of the last listing and the last modification date of every
+// known scan, indexed by scan ID.
+type SyncCursor struct {
+	Timestamp int64           `json:"timestamp"`
+	Scans     map[int64]int64 `json:"scans"`
+}
+
+// SyncStore persists the cursor of a ScanSyncer. Load must return a nil
+// cursor and no error when nothing has been saved yet.
+type SyncStore interface {
+	Load(ctx context.Context) (*SyncCursor, error)
+	Save(ctx context.Context, cursor *SyncCursor) error
+}
+
+// ScanSyncer returns the scans added, modified or deleted since its last call,
+// using the server timestamp of each listing as the next lastModificationDate.
+type ScanSyncer struct {
+	client *NessusClient
+	store  SyncStore
+	// DetectRemovals performs an additional full listing on every sync so
+	// scans permanently removed, and not only moved to the trash, are
+	// reported as deleted.
+	DetectRemovals bool
+
+	mu sync.Mutex
+}
+
+// NewScanSyncer returns a new ScanSyncer persisting its cursor in the given store
+func NewScanSyncer(client *NessusClient, store SyncStore) *ScanSyncer {
+	return &ScanSyncer{client: client, store: store}
+}
+
+// Sync returns the changes since the last call
+func (s *ScanSyncer) Sync() ([]ScanChange, error) {
+	return s.SyncContext(context.Background())
+}
+
+// SyncContext returns the changes since the last call using the given context.
+// The cursor is only saved when the whole sync succeeds, so a failed sync will
+// report the same changes again on the next call.
+func (s *ScanSyncer) SyncContext(ctx context.Context) ([]ScanChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursor, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, errors.New("Unable to load sync cursor: " + err.Error())
+	}
+	if cursor == nil {
+		cursor = &SyncCursor{}
+	}
+	if cursor.Scans == nil {
+		cursor.Scans = map[int64]int64{}
+	}
+
+	list, err := s.client.GetScanListContext(ctx, cursor.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	trash := map[int64]bool{}
+	for _, f := range list.Folders {
+		if f.Type == "trash" {
+			trash[f.ID] = true
+		}
+	}
+
+	var changes []ScanChange
+	for _, scan := range list.Scans {
+		last, known := cursor.Scans[scan.ID]
+		switch {
+		case trash[scan.FolderID]:
+			if known {
+				changes = append(changes, ScanChange{Type: ScanDeleted, Scan: scan})
+				delete(cursor.Scans, scan.ID)
+			}
+		case !known:
+			changes = append(changes, ScanChange{Type: ScanAdded, Scan: scan})
+			cursor.Scans[scan.ID] = scan.LastModificationDate
+		case scan.LastModificationDate != last:
+			changes = append(changes, ScanChange{Type: ScanModified, Scan: scan})
+			cursor.Scans[scan.ID] = scan.LastModificationDate
+		}
+	}
+
+	if s.DetectRemovals && cursor.Timestamp > 0 {
+		all, err := s.client.GetScanListContext(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		present := map[int64]bool{}
+		for _, scan := range all.Scans {
+			present[scan.ID] = true
+		}
+		for id, last := range cursor.Scans {
+			if !present[id] {
+				changes = append(changes, ScanChange{
+					Type: ScanDeleted,
+					Scan: &PersistedScan{ID: id, LastModificationDate: last},
+				})
+				delete(cursor.Scans, id)
+			}
+		}
+	}
+
+	cursor.Timestamp = list.Timestamp
+	err = s.store.Save(ctx, cursor)
+	if err != nil {
+		return nil, errors.New("Unable to save sync cursor: " + err.Error())
+	}
+
+	return changes, nil
+}
+
+// MemorySyncStore keeps the cursor in memory
+type MemorySyncStore struct {
+	mu     sync.Mutex
+	cursor *SyncCursor
+}
+
+// Load returns the last saved cursor
+func (m *MemorySyncStore) Load(_ context.Context) (*SyncCursor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cursor == nil {
+		return nil, nil
+	}
+	return copyCursor(m.cursor), nil
+}
+
+// Save stores a copy of the cursor
+func (m *MemorySyncStore) Save(_ context.Context, cursor *SyncCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursor = copyCursor(cursor)
+	return nil
+}
+
+// FileSyncStore keeps the cursor as JSON in a file
+type FileSyncStore struct {
+	Path string
+}
+
+// Load reads the cursor from the file, a missing file means no cursor
+func (f *FileSyncStore) Load(_ context.Context) (*SyncCursor, error) {
+	buf, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cursor := &SyncCursor{}
+	err = json.Unmarshal(buf, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// Save writes the cursor to a temporary file and moves it over the previous one
+func (f *FileSyncStore) Save(_ context.Context, cursor *SyncCursor) error {
+	buf, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	err = ioutil.WriteFile(tmp, buf, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+func copyCursor(c *SyncCursor) *SyncCursor {
+	cp := &SyncCursor{Timestamp: c.Timestamp, Scans: make(map[int64]int64, len(c.Scans))}
+	for id, last := range c.Scans {
+		cp.Scans[id] = last
+	}
+	return cp
+}
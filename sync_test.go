@@ -0,0 +1,90 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanSyncerSync(t *testing.T) {
+	responses := map[string]ScanList{
+		"": {
+			Timestamp: 100,
+			Folders:   []Folder{{ID: 1, Type: "main"}, {ID: 2, Type: "trash"}},
+			Scans: []*PersistedScan{
+				{ID: 1, FolderID: 1, LastModificationDate: 90},
+				{ID: 2, FolderID: 1, LastModificationDate: 95},
+			},
+		},
+		"100": {
+			Timestamp: 200,
+			Folders:   []Folder{{ID: 1, Type: "main"}, {ID: 2, Type: "trash"}},
+			Scans: []*PersistedScan{
+				{ID: 1, FolderID: 1, LastModificationDate: 150},
+				{ID: 2, FolderID: 2, LastModificationDate: 160},
+				{ID: 3, FolderID: 1, LastModificationDate: 170},
+			},
+		},
+	}
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			list, ok := responses[r.URL.Query().Get("last_modification_date")]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	store := &MemorySyncStore{}
+	s := NewScanSyncer(c, store)
+
+	tests := []struct {
+		name string
+		want []ScanChange
+	}{
+		{
+			name: "first sync",
+			want: []ScanChange{
+				{Type: ScanAdded, Scan: &PersistedScan{ID: 1}},
+				{Type: ScanAdded, Scan: &PersistedScan{ID: 2}},
+			},
+		},
+		{
+			name: "second sync",
+			want: []ScanChange{
+				{Type: ScanModified, Scan: &PersistedScan{ID: 1}},
+				{Type: ScanDeleted, Scan: &PersistedScan{ID: 2}},
+				{Type: ScanAdded, Scan: &PersistedScan{ID: 3}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		changes, err := s.Sync()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if len(changes) != len(tc.want) {
+			t.Fatalf("%s: got %d changes, expected %d", tc.name, len(changes), len(tc.want))
+		}
+		for i, ch := range changes {
+			if ch.Type != tc.want[i].Type || ch.Scan.ID != tc.want[i].Scan.ID {
+				t.Fatalf("%s: got: %v %d, expected: %v %d",
+					tc.name, ch.Type, ch.Scan.ID, tc.want[i].Type, tc.want[i].Scan.ID)
+			}
+		}
+	}
+
+	cursor, _ := store.Load(context.Background())
+	if cursor.Timestamp != 200 {
+		t.Fatalf("got cursor timestamp: %d, expected: 200", cursor.Timestamp)
+	}
+}
@@ -0,0 +1,190 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ListTagCategories returns all the tag categories
+func (c *NessusClient) ListTagCategories() ([]TagCategory, error) {
+	return c.ListTagCategoriesContext(context.Background())
+}
+
+// ListTagCategoriesContext returns all the tag categories using the given context.
+func (c *NessusClient) ListTagCategoriesContext(ctx context.Context) ([]TagCategory, error) {
+	var categories []TagCategory
+	err := c.getAllPages(ctx, "/tags/categories", nil, "categories", func(raw json.RawMessage) (int, error) {
+		var page []TagCategory
+		err := json.Unmarshal(raw, &page)
+		categories = append(categories, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// CreateTagCategory creates a tag category
+func (c *NessusClient) CreateTagCategory(name, description string) (*TagCategory, error) {
+	return c.CreateTagCategoryContext(context.Background(), name, description)
+}
+
+// CreateTagCategoryContext creates a tag category using the given context.
+func (c *NessusClient) CreateTagCategoryContext(ctx context.Context, name, description string) (*TagCategory, error) {
+	payload := map[string]string{"name": name, "description": description}
+	req, err := c.newRequest(ctx, http.MethodPost, "/tags/categories", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &TagCategory{}
+	err = c.performCallAndReadResponse(req, category)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// ListTagValues returns all the tag values, optionally restricted to a
+// category when categoryName is not empty.
+func (c *NessusClient) ListTagValues(categoryName string) ([]TagValue, error) {
+	return c.ListTagValuesContext(context.Background(), categoryName)
+}
+
+// ListTagValuesContext returns all the tag values, optionally restricted to a
+// category when categoryName is not empty, using the given context.
+func (c *NessusClient) ListTagValuesContext(ctx context.Context, categoryName string) ([]TagValue, error) {
+	q := url.Values{}
+	if categoryName != "" {
+		q.Set("f", "category_name:eq:"+categoryName)
+	}
+
+	var values []TagValue
+	err := c.getAllPages(ctx, "/tags/values", q, "values", func(raw json.RawMessage) (int, error) {
+		var page []TagValue
+		err := json.Unmarshal(raw, &page)
+		values = append(values, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// CreateTagValue creates a tag value. The category is selected by
+// CategoryUUID or, when empty, by CategoryName, which creates the category if
+// it doesn't exist. Setting Filters creates a dynamic tag.
+func (c *NessusClient) CreateTagValue(value *TagValue) (*TagValue, error) {
+	return c.CreateTagValueContext(context.Background(), value)
+}
+
+// CreateTagValueContext creates a tag value using the given context.
+func (c *NessusClient) CreateTagValueContext(ctx context.Context, value *TagValue) (*TagValue, error) {
+	if value.CategoryUUID == "" && value.CategoryName == "" {
+		return nil, errors.New("Either category UUID or category name is required")
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/tags/values", value)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &TagValue{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// DeleteTagValue removes the tag value with the given UUID
+func (c *NessusClient) DeleteTagValue(uuid string) error {
+	return c.DeleteTagValueContext(context.Background(), uuid)
+}
+
+// DeleteTagValueContext removes the tag value with the given UUID using the given context.
+func (c *NessusClient) DeleteTagValueContext(ctx context.Context, uuid string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/tags/values/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// AssignTags applies the given tag value UUIDs to the given asset UUIDs,
+// returning the UUID of the assignment job.
+func (c *NessusClient) AssignTags(assetUUIDs, tagUUIDs []string) (string, error) {
+	return c.AssignTagsContext(context.Background(), assetUUIDs, tagUUIDs)
+}
+
+// AssignTagsContext applies the given tag value UUIDs to the given asset
+// UUIDs using the given context, returning the UUID of the assignment job.
+func (c *NessusClient) AssignTagsContext(ctx context.Context, assetUUIDs, tagUUIDs []string) (string, error) {
+	return c.tagAssignments(ctx, "add", assetUUIDs, tagUUIDs)
+}
+
+// UnassignTags removes the given tag value UUIDs from the given asset UUIDs,
+// returning the UUID of the assignment job.
+func (c *NessusClient) UnassignTags(assetUUIDs, tagUUIDs []string) (string, error) {
+	return c.UnassignTagsContext(context.Background(), assetUUIDs, tagUUIDs)
+}
+
+// UnassignTagsContext removes the given tag value UUIDs from the given asset
+// UUIDs using the given context, returning the UUID of the assignment job.
+func (c *NessusClient) UnassignTagsContext(ctx context.Context, assetUUIDs, tagUUIDs []string) (string, error) {
+	return c.tagAssignments(ctx, "remove", assetUUIDs, tagUUIDs)
+}
+
+// GetTagAssignmentJob returns the status of a tag assignment job
+func (c *NessusClient) GetTagAssignmentJob(jobUUID string) (*TagAssignmentJob, error) {
+	return c.GetTagAssignmentJobContext(context.Background(), jobUUID)
+}
+
+// GetTagAssignmentJobContext returns the status of a tag assignment job using the given context.
+func (c *NessusClient) GetTagAssignmentJobContext(ctx context.Context, jobUUID string) (*TagAssignmentJob, error) {
+	path := "/tags/assets/assignments/" + url.PathEscape(jobUUID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &TagAssignmentJob{}
+	err = c.performCallAndReadResponse(req, job)
+	if err != nil {
+		return nil, err
+	}
+	job.UUID = jobUUID
+
+	return job, nil
+}
+
+func (c *NessusClient) tagAssignments(ctx context.Context, action string, assetUUIDs, tagUUIDs []string) (string, error) {
+	payload := map[string]interface{}{
+		"action": action,
+		"assets": assetUUIDs,
+		"tags":   tagUUIDs,
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, "/tags/assets/assignments", payload)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		JobUUID string `json:"job_uuid"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.JobUUID, nil
+}
@@ -0,0 +1,86 @@
+package restuss
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListTagValuesPagination(t *testing.T) {
+	values := []TagValue{{Value: "a"}, {Value: "b"}, {Value: "c"}}
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if r.URL.Path != "/tags/values" || q.Get("f") != "category_name:eq:env" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			// Pages of two values, whatever the limit requested.
+			var offset int
+			fmt.Sscan(q.Get("offset"), &offset)
+			end := offset + 2
+			if end > len(values) {
+				end = len(values)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"values":     values[offset:end],
+				"pagination": Pagination{Total: len(values), Offset: offset},
+			})
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	got, err := c.ListTagValues("env")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0].Value != "a" || got[2].Value != "c" {
+		t.Fatalf("got values: %+v, expected: %+v", got, values)
+	}
+}
+
+func TestCreateTagValueFilters(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var payload struct {
+				Filters struct {
+					Asset string `json:"asset"`
+				} `json:"filters"`
+			}
+			// The API expects the rules as a JSON encoded string.
+			err := json.Unmarshal(body, &payload)
+			if err != nil || payload.Filters.Asset == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write(body)
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	value := &TagValue{
+		CategoryName: "env",
+		Value:        "prod",
+		Filters: &TagValueFilters{Asset: TagFilterGroup{
+			Or: []TagFilterRule{{Field: "ipv4", Operator: "eq", Value: "10.0.0.1"}},
+		}},
+	}
+	created, err := c.CreateTagValue(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.Filters == nil || !reflect.DeepEqual(*created.Filters, *value.Filters) {
+		t.Fatalf("got filters: %+v, expected: %+v", created.Filters, value.Filters)
+	}
+}
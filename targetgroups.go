@@ -0,0 +1,107 @@
+package restuss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ListTargetGroups returns all the target groups
+func (c *NessusClient) ListTargetGroups() ([]TargetGroup, error) {
+	return c.ListTargetGroupsContext(context.Background())
+}
+
+// ListTargetGroupsContext returns all the target groups using the given context.
+func (c *NessusClient) ListTargetGroupsContext(ctx context.Context) ([]TargetGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/target-groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		TargetGroups []TargetGroup `json:"target_groups"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.TargetGroups, nil
+}
+
+// GetTargetGroup retrieves a target group by ID
+func (c *NessusClient) GetTargetGroup(ID int64) (*TargetGroup, error) {
+	return c.GetTargetGroupContext(context.Background(), ID)
+}
+
+// GetTargetGroupContext retrieves a target group by ID using the given context.
+func (c *NessusClient) GetTargetGroupContext(ctx context.Context, ID int64) (*TargetGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/target-groups/%d", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &TargetGroup{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// CreateTargetGroup creates a target group
+func (c *NessusClient) CreateTargetGroup(group *TargetGroup) (*TargetGroup, error) {
+	return c.CreateTargetGroupContext(context.Background(), group)
+}
+
+// CreateTargetGroupContext creates a target group using the given context.
+func (c *NessusClient) CreateTargetGroupContext(ctx context.Context, group *TargetGroup) (*TargetGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/target-groups", group)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &TargetGroup{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// EditTargetGroup updates the target group with the ID of the given group
+func (c *NessusClient) EditTargetGroup(group *TargetGroup) (*TargetGroup, error) {
+	return c.EditTargetGroupContext(context.Background(), group)
+}
+
+// EditTargetGroupContext updates the target group with the ID of the given group using the given context.
+func (c *NessusClient) EditTargetGroupContext(ctx context.Context, group *TargetGroup) (*TargetGroup, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/target-groups/%d", group.ID), group)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := &TargetGroup{}
+	err = c.performCallAndReadResponse(req, edited)
+	if err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+// DeleteTargetGroup removes the target group with the given ID
+func (c *NessusClient) DeleteTargetGroup(ID int64) error {
+	return c.DeleteTargetGroupContext(context.Background(), ID)
+}
+
+// DeleteTargetGroupContext removes the target group with the given ID using the given context.
+func (c *NessusClient) DeleteTargetGroupContext(ctx context.Context, ID int64) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/target-groups/%d", ID), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
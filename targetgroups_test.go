@@ -0,0 +1,91 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTargetGroupRequests(t *testing.T) {
+	var (
+		requests []string
+		bodies   []TargetGroup
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			switch r.Method {
+			case http.MethodGet:
+				if r.URL.Path == "/target-groups" {
+					_, _ = w.Write([]byte(`{"target_groups":[{"id":3,"name":"web","members":"10.0.0.1"}]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"id":3,"name":"web","members":"10.0.0.1"}`))
+			case http.MethodPost, http.MethodPut:
+				var g TargetGroup
+				_ = json.NewDecoder(r.Body).Decode(&g)
+				bodies = append(bodies, g)
+				g.ID = 3
+				_ = json.NewEncoder(w).Encode(g)
+			}
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	groups, err := c.ListTargetGroups()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(groups) != 1 || groups[0].ID != 3 || groups[0].Name != "web" {
+		t.Fatalf("got target groups: %+v, expected the web group", groups)
+	}
+	g, err := c.GetTargetGroup(3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.Members != "10.0.0.1" {
+		t.Fatalf("got members: %s, expected: 10.0.0.1", g.Members)
+	}
+	created, err := c.CreateTargetGroup(&TargetGroup{Name: "db", Members: "10.0.0.2", Type: "system"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.ID != 3 || created.Name != "db" {
+		t.Fatalf("got created target group: %+v", *created)
+	}
+	edited, err := c.EditTargetGroup(&TargetGroup{ID: 3, Name: "db", Members: "10.0.0.3"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if edited.Members != "10.0.0.3" {
+		t.Fatalf("got members: %s, expected: 10.0.0.3", edited.Members)
+	}
+	err = c.DeleteTargetGroup(3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{
+		"GET /target-groups",
+		"GET /target-groups/3",
+		"POST /target-groups",
+		"PUT /target-groups/3",
+		"DELETE /target-groups/3",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("got requests: %v, expected: %v", requests, want)
+	}
+	wantBodies := []TargetGroup{
+		{Name: "db", Members: "10.0.0.2", Type: "system"},
+		{ID: 3, Name: "db", Members: "10.0.0.3"},
+	}
+	if !reflect.DeepEqual(bodies, wantBodies) {
+		t.Fatalf("got bodies: %+v, expected: %+v", bodies, wantBodies)
+	}
+}
@@ -0,0 +1,300 @@
+package restuss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListUsers returns all the users
+func (c *NessusClient) ListUsers() ([]User, error) {
+	return c.ListUsersContext(context.Background())
+}
+
+// ListUsersContext returns all the users using the given context.
+func (c *NessusClient) ListUsersContext(ctx context.Context) ([]User, error) {
+	return c.getUsers(ctx, "/users")
+}
+
+// CreateUser creates a user, Username, Password and Permissions are required
+func (c *NessusClient) CreateUser(user *User) (*User, error) {
+	return c.CreateUserContext(context.Background(), user)
+}
+
+// CreateUserContext creates a user using the given context.
+func (c *NessusClient) CreateUserContext(ctx context.Context, user *User) (*User, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/users", user)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &User{}
+	err = c.performCallAndReadResponse(req, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// EditUser updates the permissions, name, email and enabled status of the
+// user with the ID of the given user
+func (c *NessusClient) EditUser(user *User) (*User, error) {
+	return c.EditUserContext(context.Background(), user)
+}
+
+// EditUserContext updates the user with the ID of the given user using the given context.
+func (c *NessusClient) EditUserContext(ctx context.Context, user *User) (*User, error) {
+	payload := map[string]interface{}{
+		"permissions": user.Permissions,
+		"name":        user.Name,
+		"email":       user.Email,
+		"enabled":     user.Enabled,
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/users/%d", user.ID), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := &User{}
+	err = c.performCallAndReadResponse(req, edited)
+	if err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+// DeleteUser removes the user with the given ID
+func (c *NessusClient) DeleteUser(ID int64) error {
+	return c.DeleteUserContext(context.Background(), ID)
+}
+
+// DeleteUserContext removes the user with the given ID using the given context.
+func (c *NessusClient) DeleteUserContext(ctx context.Context, ID int64) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/users/%d", ID), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ChangePassword changes the password of a user. currentPassword is only
+// required when changing the password of the authenticated user.
+func (c *NessusClient) ChangePassword(ID int64, password, currentPassword string) error {
+	return c.ChangePasswordContext(context.Background(), ID, password, currentPassword)
+}
+
+// ChangePasswordContext changes the password of a user using the given context.
+func (c *NessusClient) ChangePasswordContext(ctx context.Context, ID int64, password, currentPassword string) error {
+	payload := map[string]string{"password": password}
+	if currentPassword != "" {
+		payload["current_password"] = currentPassword
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/users/%d/chpasswd", ID), payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// GenerateAPIKeys generates new API keys for a user, invalidating the previous ones
+func (c *NessusClient) GenerateAPIKeys(ID int64) (*APIKeys, error) {
+	return c.GenerateAPIKeysContext(context.Background(), ID)
+}
+
+// GenerateAPIKeysContext generates new API keys for a user using the given context.
+func (c *NessusClient) GenerateAPIKeysContext(ctx context.Context, ID int64) (*APIKeys, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/users/%d/keys", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &APIKeys{}
+	err = c.performCallAndReadResponse(req, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// ListGroups returns all the user groups
+func (c *NessusClient) ListGroups() ([]Group, error) {
+	return c.ListGroupsContext(context.Background())
+}
+
+// ListGroupsContext returns all the user groups using the given context.
+func (c *NessusClient) ListGroupsContext(ctx context.Context) ([]Group, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Groups []Group `json:"groups"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Groups, nil
+}
+
+// CreateGroup creates a user group
+func (c *NessusClient) CreateGroup(name string) (*Group, error) {
+	return c.CreateGroupContext(context.Background(), name)
+}
+
+// CreateGroupContext creates a user group using the given context.
+func (c *NessusClient) CreateGroupContext(ctx context.Context, name string) (*Group, error) {
+	payload := map[string]string{"name": name}
+	req, err := c.newRequest(ctx, http.MethodPost, "/groups", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Group{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// EditGroup renames the user group with the given ID
+func (c *NessusClient) EditGroup(ID int64, name string) (*Group, error) {
+	return c.EditGroupContext(context.Background(), ID, name)
+}
+
+// EditGroupContext renames the user group with the given ID using the given context.
+func (c *NessusClient) EditGroupContext(ctx context.Context, ID int64, name string) (*Group, error) {
+	payload := map[string]string{"name": name}
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/groups/%d", ID), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Group{}
+	err = c.performCallAndReadResponse(req, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// DeleteGroup removes the user group with the given ID
+func (c *NessusClient) DeleteGroup(ID int64) error {
+	return c.DeleteGroupContext(context.Background(), ID)
+}
+
+// DeleteGroupContext removes the user group with the given ID using the given context.
+func (c *NessusClient) DeleteGroupContext(ctx context.Context, ID int64) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/groups/%d", ID), nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// ListGroupUsers returns the users member of a group
+func (c *NessusClient) ListGroupUsers(groupID int64) ([]User, error) {
+	return c.ListGroupUsersContext(context.Background(), groupID)
+}
+
+// ListGroupUsersContext returns the users member of a group using the given context.
+func (c *NessusClient) ListGroupUsersContext(ctx context.Context, groupID int64) ([]User, error) {
+	return c.getUsers(ctx, fmt.Sprintf("/groups/%d/users", groupID))
+}
+
+// AddUserToGroup adds a user to a group
+func (c *NessusClient) AddUserToGroup(groupID, userID int64) error {
+	return c.AddUserToGroupContext(context.Background(), groupID, userID)
+}
+
+// AddUserToGroupContext adds a user to a group using the given context.
+func (c *NessusClient) AddUserToGroupContext(ctx context.Context, groupID, userID int64) error {
+	path := fmt.Sprintf("/groups/%d/users/%d", groupID, userID)
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// RemoveUserFromGroup removes a user from a group
+func (c *NessusClient) RemoveUserFromGroup(groupID, userID int64) error {
+	return c.RemoveUserFromGroupContext(context.Background(), groupID, userID)
+}
+
+// RemoveUserFromGroupContext removes a user from a group using the given context.
+func (c *NessusClient) RemoveUserFromGroupContext(ctx context.Context, groupID, userID int64) error {
+	path := fmt.Sprintf("/groups/%d/users/%d", groupID, userID)
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+// GetPermissions returns the permissions granted on an object, objectType
+// being for instance "scanner", "agent-group" or "scan".
+func (c *NessusClient) GetPermissions(objectType string, objectID int64) ([]ACL, error) {
+	return c.GetPermissionsContext(context.Background(), objectType, objectID)
+}
+
+// GetPermissionsContext returns the permissions granted on an object using the given context.
+func (c *NessusClient) GetPermissionsContext(ctx context.Context, objectType string, objectID int64) ([]ACL, error) {
+	path := fmt.Sprintf("/permissions/%s/%d", url.PathEscape(objectType), objectID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		ACLs []ACL `json:"acls"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.ACLs, nil
+}
+
+// SetPermissions replaces the permissions granted on an object
+func (c *NessusClient) SetPermissions(objectType string, objectID int64, acls []ACL) error {
+	return c.SetPermissionsContext(context.Background(), objectType, objectID, acls)
+}
+
+// SetPermissionsContext replaces the permissions granted on an object using the given context.
+func (c *NessusClient) SetPermissionsContext(ctx context.Context, objectType string, objectID int64, acls []ACL) error {
+	path := fmt.Sprintf("/permissions/%s/%d", url.PathEscape(objectType), objectID)
+	payload := map[string][]ACL{"acls": acls}
+	req, err := c.newRequest(ctx, http.MethodPut, path, payload)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, nil)
+}
+
+func (c *NessusClient) getUsers(ctx context.Context, path string) ([]User, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Users []User `json:"users"`
+	}
+	err = c.performCallAndReadResponse(req, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Users, nil
+}
@@ -0,0 +1,79 @@
+package restuss
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name            string
+		currentPassword string
+		want            map[string]string
+	}{
+		{
+			name: "other user",
+			want: map[string]string{"password": "n3w"},
+		},
+		{
+			name:            "authenticated user",
+			currentPassword: "0ld",
+			want:            map[string]string{"password": "n3w", "current_password": "0ld"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got map[string]string
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != http.MethodPut || r.URL.Path != "/users/7/chpasswd" {
+						t.Errorf("got request: %s %s, expected: PUT /users/7/chpasswd", r.Method, r.URL.Path)
+					}
+					_ = json.NewDecoder(r.Body).Decode(&got)
+				}))
+			defer ts.Close()
+
+			c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+			if err != nil {
+				t.Fatalf("Error creating new client: %v", err)
+			}
+
+			err = c.ChangePassword(7, "n3w", tc.currentPassword)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got body: %v, expected: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateAPIKeys(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/users/7/keys" {
+				t.Errorf("got request: %s %s, expected: PUT /users/7/keys", r.Method, r.URL.Path)
+			}
+			_, _ = w.Write([]byte(`{"accessKey":"access","secretKey":"secret"}`))
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	keys, err := c.GenerateAPIKeys(7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := (APIKeys{AccessKey: "access", SecretKey: "secret"}); *keys != want {
+		t.Fatalf("got keys: %+v, expected: %+v", *keys, want)
+	}
+}
@@ -0,0 +1,185 @@
+package restuss
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// ScanEventType describes a scan status change
+type ScanEventType string
+
+const (
+	// ScanStarted is emitted when a scan starts or resumes running
+	ScanStarted ScanEventType = "started"
+	// ScanCompleted is emitted when a scan completes
+	ScanCompleted ScanEventType = "completed"
+	// ScanAborted is emitted when a scan is aborted
+	ScanAborted ScanEventType = "aborted"
+	// ScanPaused is emitted when a scan is paused
+	ScanPaused ScanEventType = "paused"
+	// ScanCanceled is emitted when a scan is canceled
+	ScanCanceled ScanEventType = "canceled"
+)
+
+// ScanEvent represents a scan status change. Before is nil for scans first
+// seen after the watcher started.
+type ScanEvent struct {
+	Type   ScanEventType
+	Before *PersistedScan
+	After  *PersistedScan
+}
+
+// WatchOptions configures a ScanWatcher
+type WatchOptions struct {
+	// Interval between two listings, defaults to 30 seconds.
+	Interval time.Duration
+	// BufferSize is the number of events buffered per subscriber, defaults
+	// to 100. Events are dropped for subscribers with a full buffer.
+	BufferSize int
+}
+
+// ScanWatcher polls the scan listing and emits status changes to its subscribers
+type ScanWatcher struct {
+	client *NessusClient
+	opts   WatchOptions
+
+	mu      sync.Mutex
+	subs    map[chan ScanEvent]struct{}
+	closed  bool
+	dropped int64
+}
+
+// Watch starts polling the scan listing until ctx is done, at which point
+// all subscriber channels are closed.
+func (c *NessusClient) Watch(ctx context.Context, opts WatchOptions) *ScanWatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 100
+	}
+	w := &ScanWatcher{client: c, opts: opts, subs: map[chan ScanEvent]struct{}{}}
+	go w.run(ctx)
+	return w
+}
+
+// Subscribe returns a channel receiving the events emitted from now on, and
+// a function to unsubscribe.
+func (w *ScanWatcher) Subscribe() (<-chan ScanEvent, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan ScanEvent, w.opts.BufferSize)
+	if w.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	w.subs[ch] = struct{}{}
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if _, ok := w.subs[ch]; ok {
+			delete(w.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because of full subscriber buffers
+func (w *ScanWatcher) Dropped() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+func (w *ScanWatcher) run(ctx context.Context) {
+	defer w.closeAll()
+
+	scans := map[int64]*PersistedScan{}
+	var timestamp int64
+	first := true
+
+	t := time.NewTicker(w.opts.Interval)
+	defer t.Stop()
+
+	for {
+		// Only the scans modified since the previous listing are returned,
+		// so every poll is a single cheap call regardless of the number of
+		// subscribers.
+		list, err := w.client.GetScanListContext(ctx, timestamp)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error when polling scans: %v", err)
+		} else {
+			for _, after := range list.Scans {
+				before := scans[after.ID]
+				scans[after.ID] = after
+				if first {
+					continue
+				}
+				if ev, ok := scanEvent(before, after); ok {
+					w.emit(ev)
+				}
+			}
+			timestamp = list.Timestamp
+			first = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (w *ScanWatcher) emit(ev ScanEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs {
+		select {
+		case ch <- ev:
+		default:
+			w.dropped++
+		}
+	}
+}
+
+func (w *ScanWatcher) closeAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs {
+		close(ch)
+	}
+	w.subs = map[chan ScanEvent]struct{}{}
+	w.closed = true
+}
+
+func scanEvent(before, after *PersistedScan) (ScanEvent, bool) {
+	if before != nil && before.Status == after.Status {
+		return ScanEvent{}, false
+	}
+
+	var typ ScanEventType
+	switch after.Status {
+	case "running":
+		typ = ScanStarted
+	case "completed":
+		typ = ScanCompleted
+	case "aborted":
+		typ = ScanAborted
+	case "paused":
+		typ = ScanPaused
+	case "canceled":
+		typ = ScanCanceled
+	default:
+		return ScanEvent{}, false
+	}
+
+	return ScanEvent{Type: typ, Before: before, After: after}, true
+}
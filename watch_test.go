@@ -0,0 +1,126 @@
+package restuss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newStatusServer returns a server listing a single scan whose status is the
+// next of statuses at every listing, the first listing being answered straight
+// away and the others once ready is closed
+func newStatusServer(statuses []string, ready <-chan struct{}) (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	polls := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			i := polls
+			polls++
+			mu.Unlock()
+			if i > 0 {
+				<-ready
+			}
+			if i >= len(statuses) {
+				i = len(statuses) - 1
+			}
+			_ = json.NewEncoder(w).Encode(ScanList{
+				Timestamp: int64(i + 1),
+				Scans:     []*PersistedScan{{ID: 1, Status: statuses[i]}},
+			})
+		}))
+	return ts, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+}
+
+func TestScanWatcher(t *testing.T) {
+	ready := make(chan struct{})
+	ts, _ := newStatusServer([]string{"pending", "running", "paused", "running", "completed"}, ready)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := c.Watch(ctx, WatchOptions{Interval: 5 * time.Millisecond})
+	events, unsubscribe := w.Subscribe()
+	other, _ := w.Subscribe()
+	close(ready)
+
+	// The status of the first listing is the initial one and emits nothing.
+	expected := []ScanEventType{ScanStarted, ScanPaused, ScanStarted, ScanCompleted}
+	for i, typ := range expected {
+		select {
+		case ev := <-events:
+			if ev.Type != typ || ev.After.ID != 1 || ev.Before == nil {
+				t.Fatalf("got event %d %+v, expected %s", i, ev, typ)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d %s", i, typ)
+		}
+	}
+
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Fatalf("got an event after unsubscribing, expected the channel closed")
+	}
+	unsubscribe()
+
+	cancel()
+	for i := 0; ; i++ {
+		_, ok := <-other
+		if !ok {
+			break
+		}
+		if i >= len(expected) {
+			t.Fatalf("got more events than %d", len(expected))
+		}
+	}
+	late, _ := w.Subscribe()
+	if _, ok := <-late; ok {
+		t.Fatalf("got an event from a stopped watcher, expected the channel closed")
+	}
+}
+
+func TestScanWatcherDropped(t *testing.T) {
+	ready := make(chan struct{})
+	statuses := []string{"pending", "running", "paused", "running", "completed"}
+	ts, polls := newStatusServer(statuses, ready)
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := c.Watch(ctx, WatchOptions{Interval: 5 * time.Millisecond, BufferSize: 1})
+	events, _ := w.Subscribe()
+	close(ready)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for polls() <= len(statuses) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the listings")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Only the first of the four events fits in the buffer.
+	if d := w.Dropped(); d != 3 {
+		t.Fatalf("got %d dropped events, expected 3", d)
+	}
+	ev := <-events
+	if ev.Type != ScanStarted {
+		t.Fatalf("got event %s, expected %s", ev.Type, ScanStarted)
+	}
+}
@@ -0,0 +1,255 @@
+package restuss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Workbench filter qualities
+const (
+	QualityEq     = "eq"
+	QualityNeq    = "neq"
+	QualityMatch  = "match"
+	QualityNmatch = "nmatch"
+	QualityGt     = "gt"
+	QualityLt     = "lt"
+)
+
+// WorkbenchCondition represents a single workbench filter, encoded as
+// filter.N.filter, filter.N.quality and filter.N.value
+type WorkbenchCondition struct {
+	Filter  string
+	Quality string
+	Value   string
+}
+
+// WorkbenchFilter restricts the results of workbench calls:
+//
+//	f := restuss.NewWorkbenchFilter().
+//		Where("severity", restuss.QualityEq, "Critical").
+//		Where("plugin.attributes.exploit_available", restuss.QualityEq, "true")
+type WorkbenchFilter struct {
+	Conditions []WorkbenchCondition
+	// SearchType combines the conditions with "and" (default) or "or".
+	SearchType string
+	// DateRange restricts the results to the given number of days, zero
+	// means all the results.
+	DateRange int
+}
+
+// NewWorkbenchFilter returns an empty WorkbenchFilter
+func NewWorkbenchFilter() *WorkbenchFilter {
+	return &WorkbenchFilter{}
+}
+
+// Where adds a condition to the filter
+func (f *WorkbenchFilter) Where(filter, quality, value string) *WorkbenchFilter {
+	f.Conditions = append(f.Conditions, WorkbenchCondition{Filter: filter, Quality: quality, Value: value})
+	return f
+}
+
+// MatchAny combines the conditions with "or" instead of "and"
+func (f *WorkbenchFilter) MatchAny() *WorkbenchFilter {
+	f.SearchType = "or"
+	return f
+}
+
+// Days restricts the results to the given number of days
+func (f *WorkbenchFilter) Days(days int) *WorkbenchFilter {
+	f.DateRange = days
+	return f
+}
+
+// Values returns the filter encoded as query parameters
+func (f *WorkbenchFilter) Values() url.Values {
+	q := url.Values{}
+	if f == nil {
+		return q
+	}
+	for i, c := range f.Conditions {
+		prefix := "filter." + strconv.Itoa(i) + "."
+		q.Set(prefix+"filter", c.Filter)
+		q.Set(prefix+"quality", c.Quality)
+		q.Set(prefix+"value", c.Value)
+	}
+	if len(f.Conditions) > 0 {
+		searchType := f.SearchType
+		if searchType == "" {
+			searchType = "and"
+		}
+		q.Set("filter.search_type", searchType)
+	}
+	if f.DateRange > 0 {
+		q.Set("date_range", strconv.Itoa(f.DateRange))
+	}
+	return q
+}
+
+// WorkbenchVulnerabilities returns the vulnerability counts per plugin
+// matching the filter, which can be nil
+func (c *NessusClient) WorkbenchVulnerabilities(filter *WorkbenchFilter) ([]WorkbenchVulnerability, error) {
+	return c.WorkbenchVulnerabilitiesContext(context.Background(), filter)
+}
+
+// WorkbenchVulnerabilitiesContext returns the vulnerability counts per plugin
+// matching the filter using the given context.
+func (c *NessusClient) WorkbenchVulnerabilitiesContext(ctx context.Context, filter *WorkbenchFilter) ([]WorkbenchVulnerability, error) {
+	var data struct {
+		Vulnerabilities []WorkbenchVulnerability `json:"vulnerabilities"`
+	}
+	err := c.getWorkbench(ctx, "/workbenches/vulnerabilities", filter, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Vulnerabilities, nil
+}
+
+// WorkbenchVulnerabilityInfo returns the details of a plugin across the
+// assets matching the filter, which can be nil
+func (c *NessusClient) WorkbenchVulnerabilityInfo(pluginID int64, filter *WorkbenchFilter) (*WorkbenchVulnerabilityInfo, error) {
+	return c.WorkbenchVulnerabilityInfoContext(context.Background(), pluginID, filter)
+}
+
+// WorkbenchVulnerabilityInfoContext returns the details of a plugin across
+// the assets matching the filter using the given context.
+func (c *NessusClient) WorkbenchVulnerabilityInfoContext(ctx context.Context, pluginID int64, filter *WorkbenchFilter) (*WorkbenchVulnerabilityInfo, error) {
+	var data struct {
+		Info WorkbenchVulnerabilityInfo `json:"info"`
+	}
+	path := fmt.Sprintf("/workbenches/vulnerabilities/%d/info", pluginID)
+	err := c.getWorkbench(ctx, path, filter, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.Info, nil
+}
+
+// WorkbenchAssets returns the assets matching the filter, which can be nil
+func (c *NessusClient) WorkbenchAssets(filter *WorkbenchFilter) ([]WorkbenchAsset, error) {
+	return c.WorkbenchAssetsContext(context.Background(), filter)
+}
+
+// WorkbenchAssetsContext returns the assets matching the filter using the given context.
+func (c *NessusClient) WorkbenchAssetsContext(ctx context.Context, filter *WorkbenchFilter) ([]WorkbenchAsset, error) {
+	var data struct {
+		Assets []WorkbenchAsset `json:"assets"`
+	}
+	err := c.getWorkbench(ctx, "/workbenches/assets", filter, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Assets, nil
+}
+
+// WorkbenchAssetVulnerabilities returns the vulnerability counts per plugin
+// of the asset with the given UUID matching the filter, which can be nil
+func (c *NessusClient) WorkbenchAssetVulnerabilities(assetUUID string, filter *WorkbenchFilter) ([]WorkbenchVulnerability, error) {
+	return c.WorkbenchAssetVulnerabilitiesContext(context.Background(), assetUUID, filter)
+}
+
+// WorkbenchAssetVulnerabilitiesContext returns the vulnerability counts per
+// plugin of the asset with the given UUID using the given context.
+func (c *NessusClient) WorkbenchAssetVulnerabilitiesContext(ctx context.Context, assetUUID string, filter *WorkbenchFilter) ([]WorkbenchVulnerability, error) {
+	var data struct {
+		Vulnerabilities []WorkbenchVulnerability `json:"vulnerabilities"`
+	}
+	path := "/workbenches/assets/" + url.PathEscape(assetUUID) + "/vulnerabilities"
+	err := c.getWorkbench(ctx, path, filter, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Vulnerabilities, nil
+}
+
+// WorkbenchExport requests an export of the workbench, returning the export
+// file ID. format is one of "nessus", "csv" or "html", report is
+// "vulnerabilities" and chapter, for instance, "vuln_by_plugin".
+func (c *NessusClient) WorkbenchExport(format, report, chapter string, filter *WorkbenchFilter) (int64, error) {
+	return c.WorkbenchExportContext(context.Background(), format, report, chapter, filter)
+}
+
+// WorkbenchExportContext requests an export of the workbench using the given
+// context, returning the export file ID.
+func (c *NessusClient) WorkbenchExportContext(ctx context.Context, format, report, chapter string, filter *WorkbenchFilter) (int64, error) {
+	q := filter.Values()
+	q.Set("format", format)
+	q.Set("report", report)
+	if chapter != "" {
+		q.Set("chapter", chapter)
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "/workbenches/export?"+q.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		File int64 `json:"file"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.File, nil
+}
+
+// GetWorkbenchExportStatus returns the status of a workbench export, "ready"
+// once it can be downloaded
+func (c *NessusClient) GetWorkbenchExportStatus(fileID int64) (string, error) {
+	return c.GetWorkbenchExportStatusContext(context.Background(), fileID)
+}
+
+// GetWorkbenchExportStatusContext returns the status of a workbench export using the given context.
+func (c *NessusClient) GetWorkbenchExportStatusContext(ctx context.Context, fileID int64) (string, error) {
+	path := fmt.Sprintf("/workbenches/export/%d/status", fileID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	err = c.performCallAndReadResponse(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Status, nil
+}
+
+// DownloadWorkbenchExport writes a ready workbench export to w
+func (c *NessusClient) DownloadWorkbenchExport(fileID int64, w io.Writer) error {
+	return c.DownloadWorkbenchExportContext(context.Background(), fileID, w)
+}
+
+// DownloadWorkbenchExportContext writes a ready workbench export to w using the given context.
+func (c *NessusClient) DownloadWorkbenchExportContext(ctx context.Context, fileID int64, w io.Writer) error {
+	path := fmt.Sprintf("/workbenches/export/%d/download", fileID)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	// The export isn't JSON, whatever its format.
+	req.Header.Del("Accept")
+	return c.performCallAndReadResponse(req, w)
+}
+
+func (c *NessusClient) getWorkbench(ctx context.Context, path string, filter *WorkbenchFilter, data interface{}) error {
+	if q := filter.Values().Encode(); q != "" {
+		path += "?" + q
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.performCallAndReadResponse(req, data)
+}
@@ -0,0 +1,90 @@
+package restuss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestWorkbenchFilterValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *WorkbenchFilter
+		want   url.Values
+	}{
+		{name: "nil", filter: nil, want: url.Values{}},
+		{name: "empty", filter: NewWorkbenchFilter(), want: url.Values{}},
+		{
+			name:   "days only",
+			filter: NewWorkbenchFilter().Days(30),
+			want:   url.Values{"date_range": {"30"}},
+		},
+		{
+			name: "conditions",
+			filter: NewWorkbenchFilter().
+				Where("severity", QualityEq, "Critical").
+				Where("plugin.name", QualityMatch, "OpenSSL & co"),
+			want: url.Values{
+				"filter.0.filter":    {"severity"},
+				"filter.0.quality":   {"eq"},
+				"filter.0.value":     {"Critical"},
+				"filter.1.filter":    {"plugin.name"},
+				"filter.1.quality":   {"match"},
+				"filter.1.value":     {"OpenSSL & co"},
+				"filter.search_type": {"and"},
+			},
+		},
+		{
+			name:   "match any",
+			filter: NewWorkbenchFilter().Where("severity", QualityEq, "High").MatchAny().Days(7),
+			want: url.Values{
+				"filter.0.filter":    {"severity"},
+				"filter.0.quality":   {"eq"},
+				"filter.0.value":     {"High"},
+				"filter.search_type": {"or"},
+				"date_range":         {"7"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Values(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %v, expected: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWorkbenchVulnerabilitiesQuery(t *testing.T) {
+	var got url.Values
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/workbenches/vulnerabilities" {
+				t.Errorf("got request to %s, expected: /workbenches/vulnerabilities", r.URL.Path)
+			}
+			got = r.URL.Query()
+			_, _ = w.Write([]byte(`{"vulnerabilities":[{"plugin_id":19506,"count":2}]}`))
+		}))
+	defer ts.Close()
+
+	c, err := NewClient(NewBasicAuthProvider("admin", "123"), ts.URL, true)
+	if err != nil {
+		t.Fatalf("Error creating new client: %v", err)
+	}
+
+	filter := NewWorkbenchFilter().Where("plugin.name", QualityMatch, "a+b & c").Days(1)
+	vulns, err := c.WorkbenchVulnerabilities(filter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(vulns) != 1 || vulns[0].PluginID != 19506 {
+		t.Fatalf("got: %+v, expected plugin 19506", vulns)
+	}
+	if want := filter.Values(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got query: %v, expected: %v", got, want)
+	}
+}